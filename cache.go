@@ -0,0 +1,97 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"sync"
+	"time"
+)
+
+// ttlCache caches a single value, together with the error of its retrieval, for the configured TTL.
+// It is safe for concurrent use; concurrent callers wait for a single in-flight retrieval.
+type ttlCache[T any] struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	value   T
+	err     error
+	fetched time.Time
+}
+
+// newTTLCache returns an empty ttlCache with the given TTL.
+func newTTLCache[T any](ttl time.Duration) *ttlCache[T] {
+	return &ttlCache[T]{ttl: ttl}
+}
+
+// get returns the cached value, or calls fetch to retrieve a fresh one if the cached value is older than the TTL.
+func (c *ttlCache[T]) get(fetch func() (T, error)) (T, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.fetched.IsZero() || time.Since(c.fetched) >= c.ttl {
+		c.value, c.err = fetch()
+		c.fetched = time.Now()
+	}
+	return c.value, c.err
+}
+
+// ttlCacheMap holds a separate ttlCache per key, e.g. per resolved path. To bound its memory usage,
+// e.g. if keys are derived from request data, expired entries are dropped once maxEntries is reached.
+// If all entries are still fresh, the value of a new key is retrieved without caching it, so that
+// a flood of new keys cannot evict the existing entries. It is safe for concurrent use.
+type ttlCacheMap[T any] struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*ttlCache[T]
+}
+
+// newTTLCacheMap returns an empty ttlCacheMap with the given TTL and maximum number of entries.
+func newTTLCacheMap[T any](ttl time.Duration, maxEntries int) *ttlCacheMap[T] {
+	return &ttlCacheMap[T]{ttl: ttl, maxEntries: maxEntries, entries: make(map[string]*ttlCache[T])}
+}
+
+// get returns the cached value for the given key, or calls fetch to retrieve a fresh one
+// if there is no cached value or it is older than the TTL.
+func (m *ttlCacheMap[T]) get(key string, fetch func() (T, error)) (T, error) {
+	m.mu.Lock()
+	entry, ok := m.entries[key]
+	if !ok {
+		if len(m.entries) >= m.maxEntries {
+			m.dropExpired()
+		}
+		entry = newTTLCache[T](m.ttl)
+		if len(m.entries) < m.maxEntries {
+			m.entries[key] = entry
+		}
+	}
+	m.mu.Unlock()
+
+	return entry.get(fetch)
+}
+
+// dropExpired removes all entries that are older than the TTL. The caller must hold m.mu.
+func (m *ttlCacheMap[T]) dropExpired() {
+	for key, entry := range m.entries {
+		// An entry that is locked is being retrieved, so it is about to be fresh.
+		if !entry.mu.TryLock() {
+			continue
+		}
+		expired := time.Since(entry.fetched) >= m.ttl
+		entry.mu.Unlock()
+		if expired {
+			delete(m.entries, key)
+		}
+	}
+}
@@ -16,6 +16,7 @@ package extraplaceholders
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/caddyserver/caddy/v2"
 	"github.com/caddyserver/caddy/v2/caddyconfig/caddyfile"
@@ -54,14 +55,478 @@ func (e *ExtraPlaceholders) UnmarshalCaddyfile(d *caddyfile.Dispenser) error {
 			if err1 != nil || err2 != nil {
 				return d.ArgErr()
 			}
-			e.RandIntMin = min
-			e.RandIntMax = max
+			e.RandIntMin = &min
+			e.RandIntMax = &max
+		case "rand_float_precision":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			precision, err := strconv.Atoi(d.Val())
+			if err != nil || precision < 0 {
+				return d.Errf("invalid rand_float_precision: %s", d.Val())
+			}
+			e.RandFloatPrecision = precision
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "rand_normal":
+			args := d.RemainingArgs()
+			if len(args) != 2 {
+				return d.ArgErr()
+			}
+			mean, err := strconv.ParseFloat(args[0], 64)
+			if err != nil {
+				return d.Errf("invalid rand_normal mean: %s", args[0])
+			}
+			stddev, err := strconv.ParseFloat(args[1], 64)
+			if err != nil || stddev <= 0 {
+				return d.Errf("invalid rand_normal stddev: %s", args[1])
+			}
+			e.RandNormalMean = mean
+			e.RandNormalStddev = stddev
+		case "rand_string":
+			args := d.RemainingArgs()
+			if len(args) < 1 || len(args) > 2 {
+				return d.ArgErr()
+			}
+			length, err := strconv.Atoi(args[0])
+			if err != nil || length <= 0 {
+				return d.Errf("invalid rand_string length: %s", args[0])
+			}
+			e.RandStringLength = length
+			if len(args) == 2 {
+				e.RandStringAlphabet = args[1]
+			}
+		case "rand_hex_bytes":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			n, err := strconv.Atoi(d.Val())
+			if err != nil || n <= 0 {
+				return d.Errf("invalid rand_hex_bytes: %s", d.Val())
+			}
+			e.RandHexBytes = n
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "rand_seed":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			seed, err := strconv.ParseInt(d.Val(), 10, 64)
+			if err != nil {
+				return d.Errf("invalid rand_seed: %s", d.Val())
+			}
+			e.RandSeed = seed
+		case "rand_crypto":
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+			e.RandCrypto = true
+		case "rand_choice":
+			args := d.RemainingArgs()
+			if len(args) == 0 {
+				return d.ArgErr()
+			}
+			for _, arg := range args {
+				// Split at the last colon, so values may contain colons themselves (e.g., URLs).
+				idx := strings.LastIndex(arg, ":")
+				if idx < 0 {
+					return d.Errf("invalid rand_choice %q: expected <value>:<weight>", arg)
+				}
+				weight, err := strconv.Atoi(arg[idx+1:])
+				if err != nil || weight <= 0 {
+					return d.Errf("invalid rand_choice %q: weight must be a positive integer", arg)
+				}
+				e.RandChoices = append(e.RandChoices, RandChoice{Value: arg[:idx], Weight: weight})
+			}
+		case "rand_duration":
+			args := d.RemainingArgs()
+			if len(args) != 2 {
+				return d.ArgErr()
+			}
+			min, err := caddy.ParseDuration(args[0])
+			if err != nil {
+				return d.Errf("invalid rand_duration min: %v", err)
+			}
+			max, err := caddy.ParseDuration(args[1])
+			if err != nil {
+				return d.Errf("invalid rand_duration max: %v", err)
+			}
+			e.RandDurationMin = caddy.Duration(min)
+			e.RandDurationMax = caddy.Duration(max)
+		case "rand_list":
+			items := d.RemainingArgs()
+			if len(items) == 0 {
+				return d.ArgErr()
+			}
+			e.RandList = append(e.RandList, items...)
+		case "rand_bool_probability":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			probability, err := strconv.ParseFloat(d.Val(), 64)
+			if err != nil || probability < 0 || probability > 1 {
+				return d.Errf("invalid rand_bool_probability: %s", d.Val())
+			}
+			e.RandBoolProbability = &probability
+			if d.NextArg() {
+				return d.ArgErr()
+			}
 		case "time_format_custom":
+			args := d.RemainingArgs()
+			switch len(args) {
+			case 1:
+				e.TimeFormatCustom = args[0]
+			case 2:
+				if e.TimeFormatsCustom == nil {
+					e.TimeFormatsCustom = make(map[string]string)
+				}
+				if _, exists := e.TimeFormatsCustom[args[0]]; exists {
+					return d.Errf("duplicate time_format_custom name: %s", args[0])
+				}
+				e.TimeFormatsCustom[args[0]] = args[1]
+			default:
+				return d.ArgErr()
+			}
+		case "time_zone":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			e.TimeZone = d.Val()
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "force_time_location":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			e.ForceTimeLocation = d.Val()
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "business_hours":
+			args := d.RemainingArgs()
+			if len(args) != 2 {
+				return d.ArgErr()
+			}
+			for _, arg := range args {
+				if _, err := parseClockTime(arg); err != nil {
+					return d.Errf("invalid business_hours: %s", arg)
+				}
+			}
+			e.BusinessHoursStart, e.BusinessHoursEnd = args[0], args[1]
+		case "disk_path":
+			args := d.RemainingArgs()
+			switch len(args) {
+			case 1:
+				e.DiskPath = args[0]
+			case 2:
+				if e.DiskPaths == nil {
+					e.DiskPaths = make(map[string]string)
+				}
+				if _, exists := e.DiskPaths[args[0]]; exists {
+					return d.Errf("duplicate disk_path alias: %s", args[0])
+				}
+				e.DiskPaths[args[0]] = args[1]
+			default:
+				return d.ArgErr()
+			}
+		case "loadavg_cache_ttl":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			ttl, err := caddy.ParseDuration(d.Val())
+			if err != nil {
+				return d.Errf("invalid loadavg_cache_ttl: %v", err)
+			}
+			e.LoadavgCacheTTL = caddy.Duration(ttl)
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "goroutine_soft_limit":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			limit, err := strconv.Atoi(d.Val())
+			if err != nil || limit <= 0 {
+				return d.Errf("invalid goroutine_soft_limit: %s", d.Val())
+			}
+			e.GoroutineSoftLimit = limit
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "loadavg_threshold":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			threshold, err := strconv.ParseFloat(d.Val(), 64)
+			if err != nil || threshold <= 0 {
+				return d.Errf("invalid loadavg_threshold: %s", d.Val())
+			}
+			e.LoadavgThreshold = threshold
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "loadavg_max":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			max, err := strconv.ParseFloat(d.Val(), 64)
+			if err != nil || max <= 0 {
+				return d.Errf("invalid loadavg_max: %s", d.Val())
+			}
+			e.LoadavgMax = max
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "loadavg_precision":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			precision, err := strconv.Atoi(d.Val())
+			if err != nil || precision < 0 {
+				return d.Errf("invalid loadavg_precision: %s", d.Val())
+			}
+			e.LoadavgPrecision = precision
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "refresh_interval":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			interval, err := caddy.ParseDuration(d.Val())
+			if err != nil {
+				return d.Errf("invalid refresh_interval: %v", err)
+			}
+			e.RefreshInterval = caddy.Duration(interval)
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "net_cache_ttl":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			ttl, err := caddy.ParseDuration(d.Val())
+			if err != nil {
+				return d.Errf("invalid net_cache_ttl: %v", err)
+			}
+			e.NetCacheTTL = caddy.Duration(ttl)
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "hash_buckets":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			buckets, err := strconv.Atoi(d.Val())
+			if err != nil || buckets <= 0 {
+				return d.Errf("invalid hash_buckets: %s", d.Val())
+			}
+			e.HashBuckets = buckets
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "sensors_cache_ttl":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			ttl, err := caddy.ParseDuration(d.Val())
+			if err != nil {
+				return d.Errf("invalid sensors_cache_ttl: %v", err)
+			}
+			e.SensorsCacheTTL = caddy.Duration(ttl)
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "net_interface":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			if d.Val() == "" {
+				return d.Err("net_interface must not be empty")
+			}
+			e.NetInterface = d.Val()
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "counter_start":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			start, err := strconv.ParseUint(d.Val(), 10, 64)
+			if err != nil {
+				return d.Errf("invalid counter_start: %s", d.Val())
+			}
+			e.CounterStart = start
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "seq_start":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			start, err := strconv.ParseInt(d.Val(), 10, 64)
+			if err != nil {
+				return d.Errf("invalid seq_start: %s", d.Val())
+			}
+			e.SeqStart = start
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "seq_step":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			step, err := strconv.ParseInt(d.Val(), 10, 64)
+			if err != nil {
+				return d.Errf("invalid seq_step: %s", d.Val())
+			}
+			e.SeqStep = step
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "region":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			if d.Val() == "" {
+				return d.Err("region must not be empty")
+			}
+			e.Region = d.Val()
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "env_allow":
+			names := d.RemainingArgs()
+			if len(names) == 0 {
+				return d.ArgErr()
+			}
+			e.EnvAllow = append(e.EnvAllow, names...)
+		case "read_file":
+			args := d.RemainingArgs()
+			if len(args) != 2 {
+				return d.ArgErr()
+			}
+			if e.ReadFiles == nil {
+				e.ReadFiles = make(map[string]string)
+			}
+			if _, exists := e.ReadFiles[args[0]]; exists {
+				return d.Errf("duplicate read_file alias: %s", args[0])
+			}
+			e.ReadFiles[args[0]] = args[1]
+		case "file_cache_ttl":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			ttl, err := caddy.ParseDuration(d.Val())
+			if err != nil {
+				return d.Errf("invalid file_cache_ttl: %v", err)
+			}
+			e.FileCacheTTL = caddy.Duration(ttl)
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "humanize_bytes":
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+			e.HumanizeBytes = true
+		case "set":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			key := d.Val()
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			if e.Custom == nil {
+				e.Custom = make(map[string]string)
+			}
+			if _, exists := e.Custom[key]; exists {
+				return d.Errf("duplicate set key: %s", key)
+			}
+			e.Custom[key] = d.Val()
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "prefix":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			e.Prefix = d.Val()
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "placeholders":
+			groups := d.RemainingArgs()
+			if len(groups) == 0 {
+				return d.ArgErr()
+			}
+			e.Placeholders = append(e.Placeholders, groups...)
+		case "disable_loadavg_placeholders":
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+			e.DisableLoadavgPlaceholders = true
+		case "disable_hostinfo_placeholders":
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+			e.DisableHostinfoPlaceholders = true
+		case "disk_cache_ttl":
+			if !d.NextArg() {
+				return d.ArgErr()
+			}
+			ttl, err := caddy.ParseDuration(d.Val())
+			if err != nil {
+				return d.Errf("invalid disk_cache_ttl: %v", err)
+			}
+			e.DiskCacheTTL = caddy.Duration(ttl)
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+		case "disable_cpu_placeholders":
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+			e.DisableCPUPlaceholders = true
+		case "disable_mem_placeholders":
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+			e.DisableMemPlaceholders = true
+		case "disable_net_placeholders":
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+			e.DisableNetPlaceholders = true
+		case "disable_process_placeholders":
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+			e.DisableProcessPlaceholders = true
+		case "disable_time_placeholders":
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+			e.DisableTimePlaceholders = true
+		case "disable_go_placeholders":
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+			e.DisableGoPlaceholders = true
+		case "disable_caddy_version_placeholders":
+			if d.NextArg() {
+				return d.ArgErr()
+			}
+			e.DisableCaddyVersionPlaceholders = true
+		case "disable_sensors_placeholders":
 			if d.NextArg() {
-				e.TimeFormatCustom = d.Val()
-			} else {
 				return d.ArgErr()
 			}
+			e.DisableSensorsPlaceholders = true
 		default:
 			// Handle unknown subdirective with an error message
 			return d.Errf("unknown subdirective: %s", d.Val())
@@ -0,0 +1,57 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"testing"
+
+	"github.com/caddyserver/caddy/v2/caddyconfig/caddyfile"
+)
+
+func TestUnmarshalCaddyfileRandInt(t *testing.T) {
+	tests := []struct {
+		config           string
+		wantMin, wantMax int
+		wantErr          bool
+	}{
+		{config: "rand_int 0 5", wantMin: 0, wantMax: 5},
+		{config: "rand_int 0 0", wantMin: 0, wantMax: 0},
+		{config: "rand_int -10 10", wantMin: -10, wantMax: 10},
+		{config: "rand_int 5", wantErr: true},
+		{config: "rand_int 0 5 10", wantErr: true},
+		{config: "rand_int a 5", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.config, func(t *testing.T) {
+			var e ExtraPlaceholders
+			err := e.UnmarshalCaddyfile(caddyfile.NewTestDispenser("extra_placeholders {\n" + tt.config + "\n}"))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("UnmarshalCaddyfile() error = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UnmarshalCaddyfile() error = %v", err)
+			}
+			if e.RandIntMin == nil || e.RandIntMax == nil {
+				t.Fatalf("RandIntMin = %v, RandIntMax = %v, want both set", e.RandIntMin, e.RandIntMax)
+			}
+			if *e.RandIntMin != tt.wantMin || *e.RandIntMax != tt.wantMax {
+				t.Errorf("rand_int = [%d, %d], want [%d, %d]", *e.RandIntMin, *e.RandIntMax, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
@@ -16,87 +16,655 @@ package extraplaceholders
 
 import (
 	"fmt"
+	"math/rand"
 	"net/http"
+	"os"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/caddyserver/caddy/v2"
 	"github.com/caddyserver/caddy/v2/modules/caddyhttp"
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/shirou/gopsutil/v4/host"
+	"github.com/shirou/gopsutil/v4/load"
+	psnet "github.com/shirou/gopsutil/v4/net"
+	"github.com/shirou/gopsutil/v4/process"
+	"github.com/shirou/gopsutil/v4/sensors"
 	"go.uber.org/zap"
 )
 
 // defaultTimeFormatCustom is the fallback format used if no custom format is specified for the custom time placeholders.
 const defaultTimeFormatCustom = "2006-01-02 15:04:05"
 
+// defaultRandStringLength is the fallback length of the `{extra.rand.string}` placeholder.
+const defaultRandStringLength = 16
+
+// defaultRandStringAlphabet is the fallback alphabet (URL-safe base62) of the `{extra.rand.string}` placeholder.
+const defaultRandStringAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// monoStart is the reference for the `{extra.time.mono}` placeholder. It is shared by all instances,
+// so that readings remain comparable across handlers and config reloads.
+var monoStart = time.Now()
+
+// provisionCount counts how often any ExtraPlaceholders instance has been provisioned in this process.
+var provisionCount atomic.Int64
+
+// defaultPrefix is the fallback prefix of all placeholder keys.
+const defaultPrefix = "extra"
+
+// prefixPattern is the pattern a configured prefix must match.
+var prefixPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// placeholderGroups lists the placeholder groups that can be selected via the `placeholders` directive.
+var placeholderGroups = []string{"caddy", "rand", "loadavg", "hostinfo", "host", "cpu", "mem", "net", "sensors", "process", "disk", "go", "time", "request", "hash", "trace", "file", "counter", "seq", "node", "env", "custom"}
+
+// defaultLoadavgCacheTTL is the fallback duration for which a load average reading is reused.
+const defaultLoadavgCacheTTL = 5 * time.Second
+
+// defaultDiskCacheTTL is the fallback duration for which a disk usage reading is reused per path.
+const defaultDiskCacheTTL = 5 * time.Second
+
+// maxDiskCacheEntries bounds the number of cached disk usage readings, as the disk path may be derived from request data.
+const maxDiskCacheEntries = 1000
+
+// defaultSensorsCacheTTL is the fallback duration for which a sensor temperatures reading is reused.
+const defaultSensorsCacheTTL = 10 * time.Second
+
+// usersCacheTTL is the duration for which the number of logged-in users is reused.
+const usersCacheTTL = 5 * time.Second
+
+// defaultFileCacheTTL is the fallback duration after which a file is checked for modifications.
+const defaultFileCacheTTL = 5 * time.Second
+
+// kernelStatsCacheTTL is the duration for which the kernel activity counters and the available entropy are reused.
+const kernelStatsCacheTTL = 5 * time.Second
+
+// memStatsCacheTTL is the duration for which the Go runtime memory statistics are reused,
+// as reading them briefly stops the world.
+const memStatsCacheTTL = time.Second
+
+// cpuCacheTTL is the duration for which a CPU utilization reading, of the host or the Caddy process, is reused.
+const cpuCacheTTL = time.Second
+
+// processCountCacheTTL is the duration for which the number of processes is reused, as enumerating them is costly.
+const processCountCacheTTL = 5 * time.Second
+
+// defaultNetCacheTTL is the fallback duration for which a network I/O counters reading is reused.
+const defaultNetCacheTTL = 5 * time.Second
+
+// defaultRandBoolProbability is the fallback probability of the `{extra.rand.bool}` placeholder being true.
+const defaultRandBoolProbability = 0.5
+
+// defaultRandHexBytes is the fallback number of random bytes of the `{extra.rand.hex}` placeholder.
+const defaultRandHexBytes = 16
+
+// defaultDiskPath is the fallback path used for the disk usage placeholders.
+const defaultDiskPath = "/"
+
 // ExtraPlaceholders provides additional placeholders that can be used within Caddy configurations:
 //
 // Placeholder | Description
 // ------------|-------------
 // `{extra.caddy.version.simple}` | Simple version information of the Caddy server (e.g., v2.8.4).
 // `{extra.caddy.version.full}` | Full version information of the Caddy server (e.g., v2.8.4 h1:q3pe...k=).
+// `{extra.caddy.build.go_version}` | Go version used to build the Caddy binary (e.g., go1.23.4).
+// `{extra.caddy.build.main_path}` | Module path of the main package of the Caddy binary (e.g., caddy).
+// `{extra.caddy.instance_id}` | Persistent UUID of this Caddy instance, stored in Caddy's data directory (e.g., 0f4a6d1e-8c2b-4f3a-9b7e-5d1c2a3b4c5d). Empty if it could not be retrieved.
+// `{extra.caddy.active_requests}` | Number of requests currently being handled by this handler instance, including the current one.
+// `{extra.caddy.uptime}` | Time since the handler was provisioned, i.e. Caddy was started or its config was reloaded (e.g., 3h25m10s).
+// `{extra.caddy.provision_count}` | Number of times an `extra_placeholders` handler has been provisioned in this Caddy process, which increases with every config reload.
+// `{extra.caddy.last_reload}` | Time the handler was provisioned, i.e. Caddy was started or its config was last reloaded, formatted with the `time_format_custom` format (default is RFC3339).
 // `{extra.rand.float}` | Random float value between 0.0 and 1.0.
+// `{extra.rand.normal}` | Random float from a normal (Gaussian) distribution with the mean and standard deviation configured via `rand_normal` (default is 0 and 1).
 // `{extra.rand.int}` | Random integer value between the configured min and max (default is 0 to 100).
+// `{extra.rand.int_padded}` | Same random integer as `{extra.rand.int}`, zero-padded to the number of digits of the configured max (e.g., 007 for 0 to 999).
+// `{extra.config.rand_int_min}` | Effective minimum of `{extra.rand.int}` after applying the defaults.
+// `{extra.config.rand_int_max}` | Effective maximum of `{extra.rand.int}` after applying the defaults.
+// `{extra.rand.string}` | Random string of the configured length and alphabet (default is 16 base62 characters).
+// `{extra.rand.uuid}` | Random RFC 4122 version 4 UUID, generated from a cryptographically secure source.
+// `{extra.rand.hex}` | Random hex token of the configured number of bytes (default is 16 bytes, i.e. 32 hex characters), generated from a cryptographically secure source.
+// `{extra.rand.choice}` | Random value picked from the values configured via `rand_choice`, according to their weights.
+// `{extra.rand.item}` | Uniformly picked item of the values configured with `rand_list`. Only set if configured.
+// `{extra.rand.bool}` | Random boolean that is true with the probability configured via `rand_bool_probability` (default is 0.5).
+// `{extra.rand.duration}` | Random duration between the bounds configured via `rand_duration` (e.g., 1h2m3.5s). Only set if configured.
+// `{extra.rand.duration.seconds}` | Same random duration as `{extra.rand.duration}` in whole seconds, e.g. for `max-age`. Only set if configured.
 // `{extra.loadavg.1}` | System load average over the last 1 minute.
 // `{extra.loadavg.5}` | System load average over the last 5 minutes.
 // `{extra.loadavg.15}` | System load average over the last 15 minutes.
+// `{extra.loadavg.1.normalized}` | System load average over the last 1 minute divided by the number of logical CPUs.
+// `{extra.loadavg.5.normalized}` | System load average over the last 5 minutes divided by the number of logical CPUs.
+// `{extra.loadavg.15.normalized}` | System load average over the last 15 minutes divided by the number of logical CPUs.
+// `{extra.loadavg.1.centi}` | System load average over the last 1 minute multiplied by 100 as an integer (e.g., 123 for 1.23).
+// `{extra.loadavg.5.centi}` | System load average over the last 5 minutes multiplied by 100 as an integer.
+// `{extra.loadavg.15.centi}` | System load average over the last 15 minutes multiplied by 100 as an integer.
+// `{extra.loadavg.1.over_threshold}` | Whether the system load average over the last 1 minute exceeds the configured `loadavg_threshold` (true or false). Only set if configured.
+// `{extra.loadavg.1.percent}` | System load average over the last 1 minute in percent of the configured `loadavg_max`, clamped to 0-100. Only set if configured.
+// `{extra.loadavg.all}` | System load averages over the last 1, 5 and 15 minutes as comma-separated values (e.g., 1.23,0.98,0.75).
 // `{extra.hostinfo.uptime}` | System uptime in a human-readable format.
+// `{extra.hostinfo.boottime}` | System boot time, formatted with the `time_format_custom` format (default is RFC3339).
+// `{extra.hostinfo.hostname}` | Hostname of the system.
+// `{extra.hostinfo.os}` | Operating system (e.g., linux).
+// `{extra.hostinfo.platform}` | Platform or distribution of the operating system (e.g., ubuntu).
+// `{extra.hostinfo.kernel_version}` | Kernel version of the operating system (e.g., 6.8.0-45-generic).
+// `{extra.hostinfo.local_ip}` | Primary outbound IP address of the system (empty if it cannot be determined).
+// `{extra.hostinfo.users}` | Number of users currently logged in to the system.
+// `{extra.host.context_switches}` | Total number of context switches since boot (Linux only, empty otherwise).
+// `{extra.host.interrupts}` | Total number of interrupts since boot (Linux only, empty otherwise).
+// `{extra.host.entropy_avail}` | Available entropy of the kernel's random number generator in bits (Linux only, empty otherwise).
+// `{extra.host.cgroup_cpu_quota}` | Effective CPU limit of the cgroup (v2) of the Caddy process in number of CPUs (e.g., 1.5), or `max` if unlimited (Linux only, empty otherwise).
+// `{extra.host.virtualization}` | Detected virtualization or container system (e.g., docker, kvm). Empty on bare metal.
+// `{extra.host.virtualization_role}` | Role of the system in the detected virtualization, `guest` or `host`. Empty on bare metal.
+// `{extra.host.process_count}` | Number of processes running on the system.
+// `{extra.cpu.percent}` | Overall CPU utilization in percent since the previous sample (at most once per second).
+// `{extra.cpu.percent.<n>}` | Utilization of the logical CPU with index n in percent since the previous sample.
+// `{extra.cpu.count}` | Number of logical CPU cores.
+// `{extra.mem.swap_total}` | Total swap space in bytes.
+// `{extra.mem.swap_used}` | Used swap space in bytes.
+// `{extra.mem.swap_used_percent}` | Used swap space in percent.
+// `{extra.net.bytes_sent}` | Total number of bytes sent across all network interfaces, or the interface configured via `net_interface`.
+// `{extra.net.bytes_recv}` | Total number of bytes received across all network interfaces, or the interface configured via `net_interface`.
+// `{extra.sensors.temp.<key>}` | Current temperature in degrees Celsius of the hardware sensor with the given key (e.g., `{extra.sensors.temp.coretemp_package_id_0}`), if available.
+// `{extra.sensors.cpu_temp}` | Average current temperature in degrees Celsius of all sensors whose key contains "core" or "cpu", empty if there are none.
+// `{extra.process.cpu_percent}` | CPU utilization of the Caddy process in percent since the previous sample (at most once per second, 100% equals one fully used core).
+// `{extra.process.mem_rss}` | Resident set size (RSS) of the Caddy process in bytes.
+// `{extra.process.num_threads}` | Number of OS threads of the Caddy process.
+// `{extra.process.num_fds}` | Number of open file descriptors of the Caddy process (Linux only, empty otherwise).
+// `{extra.process.start_time}` | Time the handler was provisioned, i.e. Caddy was started or its config was reloaded, formatted with the `time_format_custom` format (default is RFC3339).
+// `{extra.go.runtime.numcpu}` | Number of logical CPUs usable by the Caddy process.
+// `{extra.go.runtime.gomaxprocs}` | Maximum number of CPUs executing Go code simultaneously (GOMAXPROCS).
+// `{extra.go.runtime.numcgocall}` | Number of cgo calls made by the Caddy process.
+// `{extra.go.runtime.goroutine_percent}` | Number of goroutines in percent of the configured `goroutine_soft_limit`, rounded to an integer. Only set if configured.
+// `{extra.go.gc.pause_last}` | Duration of the last garbage collection pause (e.g., 52.3µs).
+// `{extra.go.gc.pause_total}` | Total duration of all garbage collection pauses since Caddy was started.
+// `{extra.counter}` | Monotonically increasing request counter of this handler instance, starting at the configured `counter_start` (default is 0).
+// `{extra.seq.next}` | Sequence of this handler instance, starting at the configured `seq_start` (default is 0) and incremented by `seq_step` (default is 1) with every request.
+// `{extra.time.mono}` | Monotonic clock reading in nanoseconds when the handler was entered, for calculating durations between two readings.
+// `{extra.node.region}` | Region of this node as configured with the `region` subdirective. Only set if configured.
+// `{extra.env.<name>}` | Current value of the environment variable with the given name, read per request. Only set for names allowed with `env_allow`.
+// `{extra.file.<alias>}` | Trimmed contents of the file configured with `read_file <alias> <path>`, limited to 64 KiB. Empty if the file cannot be read.
+// `{extra.custom.<key>}` | Value defined with the `set` subdirective, with placeholders resolved per request.
+// `{extra.request.tls.version}` | TLS version of the current request (e.g., 1.3), empty for plaintext requests.
+// `{extra.request.tls.cipher_suite}` | TLS cipher suite of the current request (e.g., TLS_AES_128_GCM_SHA256), empty for plaintext requests.
+// `{extra.request.elapsed}` | Time elapsed since Caddy started handling the current request (e.g., 1.234ms).
+// `{extra.request.base_url}` | Absolute base URL of the current request, composed of scheme and host including the port, if sent by the client (e.g., https://example.com:8443).
+// `{extra.request.client_port}` | Port of the remote address of the current request, empty if the remote address has no port.
+// `{extra.server.local_addr}` | Local address of the connection of the current request, i.e. the listener socket the client connected to (e.g., 192.0.2.1:443).
+// `{extra.server.name}` | Name of the Caddy server handling the current request, as configured in the JSON config or generated from the Caddyfile (e.g., srv0). Falls back to `{extra.server.local_addr}` if unavailable.
+// `{extra.hash.client_ip}` | Stable FNV-1a hash of the client IP in hexadecimal.
+// `{extra.hash.client_ip.bucket}` | Bucket of the client IP in the range [0, `hash_buckets`), derived from its hash. Only set if `hash_buckets` is configured.
+// `{extra.trace.id}` | Random 128-bit trace ID per request in hexadecimal (32 characters), like an OpenTelemetry trace ID.
+// `{extra.trace.span}` | Random 64-bit span ID per request in hexadecimal (16 characters), like an OpenTelemetry span ID.
+// `{extra.disk.total}` | Total size in bytes of the disk containing the configured `disk_path` (default is /).
+// `{extra.disk.free}` | Free space in bytes of the disk containing the configured `disk_path`.
+// `{extra.disk.used}` | Used space in bytes of the disk containing the configured `disk_path`.
+// `{extra.disk.used_percent}` | Used space in percent of the disk containing the configured `disk_path`.
+// `{extra.disk.inodes_total}` | Total number of inodes of the disk containing the configured `disk_path`, empty if not supported by the file system.
+// `{extra.disk.inodes_free}` | Number of free inodes of the disk containing the configured `disk_path`, empty if not supported by the file system.
+// `{extra.disk.inodes_used_percent}` | Used inodes in percent of the disk containing the configured `disk_path`, empty if not supported by the file system.
+// `{extra.disk.<alias>.*}` | All of the above `{extra.disk.*}` placeholders for the path configured with `disk_path <alias> <path>`.
 // `{extra.newline}` | Newline character (\n).
 //
 // Current local time placeholders:
 //
 // Placeholder | Description
 // ------------|-------------
+// `{extra.time.now.year}` | Current year as an integer (e.g., 2024).
+// `{extra.time.now.year_short}` | Current year as a zero-padded two-digit string (e.g., "24" for 2024).
 // `{extra.time.now.month}` | Current month as an integer (e.g., 5 for May).
 // `{extra.time.now.month_padded}` | Current month as a zero-padded string (e.g., "05" for May).
 // `{extra.time.now.day}` | Current day of the month as an integer.
 // `{extra.time.now.day_padded}` | Current day of the month as a zero-padded string.
 // `{extra.time.now.hour}` | Current hour in 24-hour format as an integer.
 // `{extra.time.now.hour_padded}` | Current hour in 24-hour format as a zero-padded string.
+// `{extra.time.now.hour12}` | Current hour in 12-hour format as an integer (1-12).
+// `{extra.time.now.hour12_padded}` | Current hour in 12-hour format as a zero-padded string.
+// `{extra.time.now.ampm}` | "AM" or "PM" for the current hour.
 // `{extra.time.now.minute}` | Current minute as an integer.
 // `{extra.time.now.minute_padded}` | Current minute as a zero-padded string.
 // `{extra.time.now.second}` | Current second as an integer.
 // `{extra.time.now.second_padded}` | Current second as a zero-padded string.
+// `{extra.time.now.millisecond}` | Millisecond within the current second as an integer (0-999).
+// `{extra.time.now.microsecond}` | Microsecond within the current second as an integer (0-999999).
+// `{extra.time.now.nanosecond}` | Nanosecond within the current second as an integer (0-999999999).
 // `{extra.time.now.timezone_offset}` | Current timezone offset from UTC (e.g., +0200).
-// `{extra.time.now.timezone_name}` | Current timezone abbreviation (e.g., CEST).
+// `{extra.time.now.timezone_offset_minutes}` | Current timezone offset from UTC in minutes (e.g., 120 for +0200).
+// `{extra.time.now.timezone_name}` | Current timezone abbreviation (e.g., CEST), or the IANA location name if no abbreviation is known.
+// `{extra.time.now.seconds_to_midnight}` | Number of full seconds until the next midnight (e.g., 3600 at 23:00:00).
 // `{extra.time.now.iso_week}` | Current ISO week number of the year.
 // `{extra.time.now.iso_year}` | ISO year corresponding to the current ISO week.
+// `{extra.time.now.iso_week_padded}` | Current ISO week number of the year as a zero-padded two-digit string (e.g., "03").
+// `{extra.time.now.iso_yearweek}` | Current ISO year and week in ISO 8601 week format (e.g., 2024-W03).
 // `{extra.time.now.weekday_int}` | Current day of the week as an integer (Sunday = 0, Monday = 1, ..., Saturday = 6).
+// `{extra.time.now.weekday}` | Current day of the week as its English name (e.g., Monday).
+// `{extra.time.now.weekday_num}` | Current day of the week as an integer, following Go's convention (Sunday = 0, Monday = 1, ..., Saturday = 6).
+// `{extra.time.now.weekday_num_iso}` | Current day of the week as an ISO 8601 integer (Monday = 1, ..., Sunday = 7).
+// `{extra.time.now.is_weekend}` | Whether the current day is Saturday or Sunday (`true` or `false`).
+// `{extra.time.now.is_weekday}` | Whether the current day is Monday to Friday (`true` or `false`).
+// `{extra.time.now.is_business_hours}` | Whether the current time is within the business hours configured via `business_hours` on a weekday (`true` or `false`). Only set if configured.
+// `{extra.time.now.quarter}` | Current quarter of the year as an integer (1-4).
+// `{extra.time.now.quarter_label}` | Current quarter of the year as a label (e.g., Q3).
+// `{extra.time.now.year_day}` | Current day of the year as an integer (1-366).
+// `{extra.time.now.ordinal_date}` | Current date in ISO 8601 ordinal format (e.g., 2024-287).
+// `{extra.time.now.days_in_month}` | Number of days in the current month (28-31, accounting for leap years).
+// `{extra.time.now.rfc3339}` | Current time in RFC 3339 format (e.g., 2024-05-01T14:30:00+02:00).
+// `{extra.time.now.rfc1123}` | Current time in RFC 1123 format (e.g., Wed, 01 May 2024 14:30:00 CEST).
+// `{extra.time.now.unix}` | Current time as Unix timestamp in seconds since the epoch.
+// `{extra.time.now.unix_milli}` | Current time as Unix timestamp in milliseconds since the epoch.
 // `{extra.time.now.custom}` | Current time in a custom format, configurable via the `time_format_custom` directive.
+// `{extra.time.now.custom_urlenc}` | Current time in the custom format of `{extra.time.now.custom}`, URL-encoded for use in query strings.
+// `{extra.time.now.custom_epoch}` | Unix epoch (1970-01-01 00:00:00 UTC) in the server’s local timezone, formatted with the `time_format_custom` format, e.g. for checking the layout.
+// `{extra.time.now.custom.<name>}` | Current time in the named custom format, configurable via the `time_format_custom <name> <layout>` directive.
 //
 // UTC equivalents of the current time placeholders (with `.utc` added):
 //
 // Placeholder | Description
 // ------------|-------------
+// `{extra.time.now.utc.year}` | Current year in UTC as an integer (e.g., 2024).
+// `{extra.time.now.utc.year_short}` | Current year in UTC as a zero-padded two-digit string (e.g., "24" for 2024).
 // `{extra.time.now.utc.month}` | Current month in UTC as an integer (e.g., 5 for May).
 // `{extra.time.now.utc.month_padded}` | Current month in UTC as a zero-padded string (e.g., "05" for May).
 // `{extra.time.now.utc.day}` | Current day of the month in UTC as an integer.
 // `{extra.time.now.utc.day_padded}` | Current day of the month in UTC as a zero-padded string.
 // `{extra.time.now.utc.hour}` | Current hour in UTC in 24-hour format as an integer.
 // `{extra.time.now.utc.hour_padded}` | Current hour in UTC in 24-hour format as a zero-padded string.
+// `{extra.time.now.utc.hour12}` | Current hour in UTC in 12-hour format as an integer (1-12).
+// `{extra.time.now.utc.hour12_padded}` | Current hour in UTC in 12-hour format as a zero-padded string.
+// `{extra.time.now.utc.ampm}` | "AM" or "PM" for the current hour in UTC.
 // `{extra.time.now.utc.minute}` | Current minute in UTC as an integer.
 // `{extra.time.now.utc.minute_padded}` | Current minute in UTC as a zero-padded string.
 // `{extra.time.now.utc.second}` | Current second in UTC as an integer.
 // `{extra.time.now.utc.second_padded}` | Current second in UTC as a zero-padded string.
+// `{extra.time.now.utc.millisecond}` | Millisecond within the current second in UTC as an integer (0-999).
+// `{extra.time.now.utc.microsecond}` | Microsecond within the current second in UTC as an integer (0-999999).
+// `{extra.time.now.utc.nanosecond}` | Nanosecond within the current second in UTC as an integer (0-999999999).
 // `{extra.time.now.utc.timezone_offset}` | UTC timezone offset (always +0000).
+// `{extra.time.now.utc.timezone_offset_minutes}` | UTC timezone offset in minutes (always 0).
 // `{extra.time.now.utc.timezone_name}` | UTC timezone abbreviation (always UTC).
+// `{extra.time.now.utc.seconds_to_midnight}` | Number of full seconds until the next midnight UTC (e.g., 3600 at 23:00:00 UTC).
 // `{extra.time.now.utc.iso_week}` | Current ISO week number of the year in UTC.
 // `{extra.time.now.utc.iso_year}` | ISO year corresponding to the current ISO week in UTC.
+// `{extra.time.now.utc.iso_week_padded}` | Current ISO week number of the year in UTC as a zero-padded two-digit string (e.g., "03").
+// `{extra.time.now.utc.iso_yearweek}` | Current ISO year and week in UTC in ISO 8601 week format (e.g., 2024-W03).
 // `{extra.time.now.utc.weekday_int}` | Current day of the week in UTC as an integer (Sunday = 0, Monday = 1, ..., Saturday = 6).
+// `{extra.time.now.utc.weekday}` | Current day of the week in UTC as its English name (e.g., Monday).
+// `{extra.time.now.utc.weekday_num}` | Current day of the week in UTC as an integer, following Go's convention (Sunday = 0, Monday = 1, ..., Saturday = 6).
+// `{extra.time.now.utc.weekday_num_iso}` | Current day of the week in UTC as an ISO 8601 integer (Monday = 1, ..., Sunday = 7).
+// `{extra.time.now.utc.is_weekend}` | Whether the current UTC day is Saturday or Sunday (`true` or `false`).
+// `{extra.time.now.utc.is_weekday}` | Whether the current UTC day is Monday to Friday (`true` or `false`).
+// `{extra.time.now.utc.is_business_hours}` | Whether the current UTC time is within the business hours configured via `business_hours` on a weekday (`true` or `false`). Only set if configured.
+// `{extra.time.now.utc.quarter}` | Current quarter of the year in UTC as an integer (1-4).
+// `{extra.time.now.utc.quarter_label}` | Current quarter of the year in UTC as a label (e.g., Q3).
+// `{extra.time.now.utc.year_day}` | Current day of the year in UTC as an integer (1-366).
+// `{extra.time.now.utc.ordinal_date}` | Current date in UTC in ISO 8601 ordinal format (e.g., 2024-287).
+// `{extra.time.now.utc.days_in_month}` | Number of days in the current month in UTC (28-31, accounting for leap years).
+// `{extra.time.now.utc.rfc3339}` | Current UTC time in RFC 3339 format (e.g., 2024-05-01T12:30:00Z).
+// `{extra.time.now.utc.rfc1123}` | Current UTC time in RFC 1123 format (e.g., Wed, 01 May 2024 12:30:00 UTC).
+// `{extra.time.now.utc.unix}` | Current time as Unix timestamp in seconds since the epoch (same as the local variant).
+// `{extra.time.now.utc.unix_milli}` | Current time as Unix timestamp in milliseconds since the epoch (same as the local variant).
 // `{extra.time.now.utc.custom}` | Current UTC time in a custom format, configurable via the `time_format_custom` directive.
+// `{extra.time.now.utc.custom_urlenc}` | Current UTC time in the custom format of `{extra.time.now.utc.custom}`, URL-encoded for use in query strings.
+// `{extra.time.now.utc.custom_epoch}` | Unix epoch (1970-01-01 00:00:00 UTC), formatted with the `time_format_custom` format, e.g. for checking the layout.
+// `{extra.time.now.utc.custom.<name>}` | Current UTC time in the named custom format, configurable via the `time_format_custom <name> <layout>` directive.
+//
+// If a timezone is configured via the `time_zone` directive, all current time placeholders are
+// additionally available for that timezone with `.tz` added (e.g., `{extra.time.now.tz.hour}`).
 type ExtraPlaceholders struct {
 	// RandIntMin defines the minimum value (inclusive) for the `{extra.rand.int}` placeholder.
-	RandIntMin int `json:"rand_int_min,omitempty"`
+	// If left unset, a default minimum of 0 is used. An explicit 0 is kept as configured.
+	RandIntMin *int `json:"rand_int_min,omitempty"`
 
 	// RandIntMax defines the maximum value (inclusive) for the `{extra.rand.int}` placeholder.
-	RandIntMax int `json:"rand_int_max,omitempty"`
+	// If left unset, a default maximum of 100 is used. An explicit 0 is kept as configured.
+	RandIntMax *int `json:"rand_int_max,omitempty"`
+
+	// RandFloatPrecision defines the number of decimal digits of the `{extra.rand.float}` placeholder.
+	// If left empty, the float is output with full precision.
+	RandFloatPrecision int `json:"rand_float_precision,omitempty"`
+
+	// RandNormalMean and RandNormalStddev define the normal distribution of the `{extra.rand.normal}` placeholder.
+	// If left empty, a mean of 0 and a standard deviation of 1 are used.
+	RandNormalMean   float64 `json:"rand_normal_mean,omitempty"`
+	RandNormalStddev float64 `json:"rand_normal_stddev,omitempty"`
+
+	// RandStringLength defines the length of the `{extra.rand.string}` placeholder.
+	// If left empty, a default length of 16 is used.
+	RandStringLength int `json:"rand_string_length,omitempty"`
+
+	// RandStringAlphabet defines the characters used for the `{extra.rand.string}` placeholder.
+	// If left empty, the URL-safe base62 alphabet (0-9, A-Z, a-z) is used.
+	RandStringAlphabet string `json:"rand_string_alphabet,omitempty"`
+
+	// RandHexBytes defines the number of random bytes of the `{extra.rand.hex}` placeholder,
+	// which is twice as many hex characters long. If left empty, a default of 16 bytes is used.
+	RandHexBytes int `json:"rand_hex_bytes,omitempty"`
+
+	// RandSeed seeds the random source of the `{extra.rand.*}` placeholders, which makes their output reproducible.
+	// If left empty, the random source is seeded with the current time.
+	RandSeed int64 `json:"rand_seed,omitempty"`
+
+	// RandCrypto generates the `{extra.rand.float}`, `{extra.rand.int}` and `{extra.rand.string}` placeholders
+	// from the cryptographically secure crypto/rand instead of math/rand. This makes them suitable for
+	// security tokens, at the cost of noticeably slower generation. RandSeed has no effect if enabled.
+	RandCrypto bool `json:"rand_crypto,omitempty"`
+
+	// RandChoices defines the weighted values for the `{extra.rand.choice}` placeholder.
+	RandChoices []RandChoice `json:"rand_choices,omitempty"`
+
+	// RandDurationMin and RandDurationMax define the range for the `{extra.rand.duration}` placeholder.
+	// If both are left empty, the placeholder is not set.
+	RandDurationMin caddy.Duration `json:"rand_duration_min,omitempty"`
+	RandDurationMax caddy.Duration `json:"rand_duration_max,omitempty"`
+
+	// RandList defines the items for the `{extra.rand.item}` placeholder, which are picked uniformly.
+	RandList []string `json:"rand_list,omitempty"`
+
+	// RandBoolProbability defines the probability (0.0-1.0) of the `{extra.rand.bool}` placeholder being true.
+	// If left unset, a default probability of 0.5 is used. An explicit 0 is kept as configured.
+	RandBoolProbability *float64 `json:"rand_bool_probability,omitempty"`
 
 	// TimeFormatCustom specifies a custom time format for the `{extra.time.now.custom}` and `{extra.time.now.utc.custom}` placeholder.
 	// If left empty, a default format of "2006-01-02 15:04:05" is used.
 	TimeFormatCustom string `json:"time_format_custom,omitempty"`
 
+	// TimeFormatsCustom specifies additional named custom time formats for the `{extra.time.now.custom.<name>}`
+	// and `{extra.time.now.utc.custom.<name>}` placeholders, keyed by name.
+	TimeFormatsCustom map[string]string `json:"time_formats_custom,omitempty"`
+
+	// TimeZone specifies an IANA timezone name (e.g., "Europe/Berlin") for the `{extra.time.now.tz.*}` placeholders.
+	// If left empty, the `{extra.time.now.tz.*}` placeholders are not set.
+	TimeZone string `json:"time_zone,omitempty"`
+
+	// ForceTimeLocation specifies an IANA timezone name that is used instead of the server's local time
+	// for the `{extra.time.now.*}` placeholders. If left empty, the server's local time is used.
+	ForceTimeLocation string `json:"force_time_location,omitempty"`
+
+	// BusinessHoursStart and BusinessHoursEnd define the daily business hours (24h HH:MM) for the
+	// `{extra.time.now.is_business_hours}` placeholder, which is only true on weekdays.
+	// If left empty, the placeholder is not set.
+	BusinessHoursStart string `json:"business_hours_start,omitempty"`
+	BusinessHoursEnd   string `json:"business_hours_end,omitempty"`
+
+	// DiskPath specifies the path for the `{extra.disk.*}` placeholders.
+	// It may contain placeholders (e.g., "/data/{http.request.host}"), which are resolved per request.
+	// If left empty, a default path of "/" is used.
+	DiskPath string `json:"disk_path,omitempty"`
+
+	// DiskPaths maps aliases to additional paths for the `{extra.disk.<alias>.*}` placeholders,
+	// e.g. for separate data and log volumes. The paths may contain placeholders as well.
+	DiskPaths map[string]string `json:"disk_paths,omitempty"`
+
+	// DiskCacheTTL defines how long a disk usage reading is reused per resolved disk path.
+	// If left empty, a default TTL of 5 seconds is used.
+	DiskCacheTTL caddy.Duration `json:"disk_cache_ttl,omitempty"`
+
+	// LoadavgCacheTTL defines how long a load average reading is reused for the `{extra.loadavg.*}` placeholders.
+	// If left empty, a default TTL of 5 seconds is used.
+	LoadavgCacheTTL caddy.Duration `json:"loadavg_cache_ttl,omitempty"`
+
+	// LoadavgThreshold defines the 1-minute load average above which the `{extra.loadavg.1.over_threshold}`
+	// placeholder is true. If left empty, the placeholder is not set.
+	LoadavgThreshold float64 `json:"loadavg_threshold,omitempty"`
+
+	// LoadavgMax defines the 1-minute load average that corresponds to 100% for the `{extra.loadavg.1.percent}`
+	// placeholder. If left empty, the placeholder is not set.
+	LoadavgMax float64 `json:"loadavg_max,omitempty"`
+
+	// LoadavgPrecision defines the number of decimal digits of the raw and normalized `{extra.loadavg.*}` placeholders.
+	// If left empty, the load averages are output with full precision.
+	LoadavgPrecision int `json:"loadavg_precision,omitempty"`
+
+	// GoroutineSoftLimit defines the number of goroutines that corresponds to 100% for the
+	// `{extra.go.runtime.goroutine_percent}` placeholder. If left empty, the placeholder is not set.
+	GoroutineSoftLimit int `json:"goroutine_soft_limit,omitempty"`
+
+	// RefreshInterval enables a background refresh of the `{extra.loadavg.*}`, `{extra.mem.*}` and `{extra.cpu.*}`
+	// placeholders at the given interval, so that requests don't need any system calls for them.
+	// If left empty, these placeholders are retrieved on demand.
+	RefreshInterval caddy.Duration `json:"refresh_interval,omitempty"`
+
+	// NetCacheTTL defines how long a network I/O counters reading is reused for the `{extra.net.*}` placeholders.
+	// If left empty, a default TTL of 5 seconds is used.
+	NetCacheTTL caddy.Duration `json:"net_cache_ttl,omitempty"`
+
+	// NetInterface selects the network interface (e.g., "eth0") for the `{extra.net.*}` placeholders.
+	// If left empty, the counters are aggregated across all interfaces.
+	NetInterface string `json:"net_interface,omitempty"`
+
+	// HashBuckets defines the number of buckets for the `{extra.hash.client_ip.bucket}` placeholder.
+	// If left empty, the placeholder is not set.
+	HashBuckets int `json:"hash_buckets,omitempty"`
+
+	// SensorsCacheTTL defines how long a sensor temperatures reading is reused for the `{extra.sensors.*}` placeholders.
+	// If left empty, a default TTL of 10 seconds is used.
+	SensorsCacheTTL caddy.Duration `json:"sensors_cache_ttl,omitempty"`
+
+	// CounterStart defines the first value of the `{extra.counter}` placeholder.
+	CounterStart uint64 `json:"counter_start,omitempty"`
+
+	// SeqStart defines the first value of the `{extra.seq.next}` placeholder.
+	SeqStart int64 `json:"seq_start,omitempty"`
+
+	// SeqStep defines the increment of the `{extra.seq.next}` placeholder per request. It may be negative.
+	// If left empty, a default step of 1 is used.
+	SeqStep int64 `json:"seq_step,omitempty"`
+
+	// Region defines the region of this node for the `{extra.node.region}` placeholder.
+	// If left empty, the placeholder is not set.
+	Region string `json:"region,omitempty"`
+
+	// EnvAllow lists the environment variables that are read per request for the `{extra.env.<name>}` placeholders.
+	// Other environment variables are not exposed.
+	EnvAllow []string `json:"env_allow,omitempty"`
+
+	// ReadFiles maps aliases to the paths of files whose contents are exposed as `{extra.file.<alias>}` placeholders.
+	ReadFiles map[string]string `json:"read_files,omitempty"`
+
+	// FileCacheTTL defines how long the contents of a file are reused before checking it for modifications.
+	// If left empty, a default TTL of 5 seconds is used.
+	FileCacheTTL caddy.Duration `json:"file_cache_ttl,omitempty"`
+
+	// HumanizeBytes additionally sets a `.human` variant of each size placeholder (e.g., `{extra.disk.free.human}`)
+	// with the size formatted using IEC binary prefixes (e.g., "15.6 GiB").
+	HumanizeBytes bool `json:"humanize_bytes,omitempty"`
+
+	// Custom defines values for the `{extra.custom.<key>}` placeholders, keyed by name.
+	// Placeholders within the values are resolved per request.
+	Custom map[string]string `json:"custom,omitempty"`
+
+	// Prefix defines the prefix of all placeholder keys, e.g. `{myprefix.rand.int}` instead of `{extra.rand.int}`.
+	// If left empty, a default prefix of "extra" is used.
+	Prefix string `json:"prefix,omitempty"`
+
+	// Placeholders restricts the placeholder groups that are set for each request
+	// (caddy, rand, loadavg, hostinfo, host, cpu, mem, net, sensors, process, disk, go, time, request, hash, trace, file,
+	// counter, seq, node, env, custom).
+	// If left empty, all groups are set.
+	Placeholders []string `json:"placeholders,omitempty"`
+
+	// DisableLoadavgPlaceholders disables the `{extra.loadavg.*}` placeholders.
+	DisableLoadavgPlaceholders bool `json:"disable_loadavg_placeholders,omitempty"`
+
+	// DisableHostinfoPlaceholders disables the `{extra.hostinfo.*}` placeholders.
+	DisableHostinfoPlaceholders bool `json:"disable_hostinfo_placeholders,omitempty"`
+
+	// DisableCPUPlaceholders disables the `{extra.cpu.*}` placeholders.
+	DisableCPUPlaceholders bool `json:"disable_cpu_placeholders,omitempty"`
+
+	// DisableMemPlaceholders disables the `{extra.mem.*}` placeholders.
+	DisableMemPlaceholders bool `json:"disable_mem_placeholders,omitempty"`
+
+	// DisableNetPlaceholders disables the `{extra.net.*}` placeholders.
+	DisableNetPlaceholders bool `json:"disable_net_placeholders,omitempty"`
+
+	// DisableProcessPlaceholders disables the `{extra.process.*}` placeholders.
+	DisableProcessPlaceholders bool `json:"disable_process_placeholders,omitempty"`
+
+	// DisableTimePlaceholders disables the `{extra.time.now.*}` placeholders.
+	DisableTimePlaceholders bool `json:"disable_time_placeholders,omitempty"`
+
+	// DisableGoPlaceholders disables the `{extra.go.*}` placeholders.
+	DisableGoPlaceholders bool `json:"disable_go_placeholders,omitempty"`
+
+	// DisableCaddyVersionPlaceholders disables the `{extra.caddy.version.*}` placeholders.
+	DisableCaddyVersionPlaceholders bool `json:"disable_caddy_version_placeholders,omitempty"`
+
+	// DisableSensorsPlaceholders disables the `{extra.sensors.*}` placeholders.
+	DisableSensorsPlaceholders bool `json:"disable_sensors_placeholders,omitempty"`
+
+	// randIntMin and randIntMax are the effective bounds of the `{extra.rand.int}` placeholder after defaulting.
+	randIntMin int
+	randIntMax int
+
+	// randIntPadWidth is the number of digits of randIntMax, used for the `{extra.rand.int_padded}` placeholder.
+	randIntPadWidth int
+
+	// randBoolProbability is the effective probability of the `{extra.rand.bool}` placeholder after defaulting.
+	randBoolProbability float64
+
+	// enabledGroups is the set of placeholder groups from Placeholders. It is nil if all groups are enabled.
+	enabledGroups map[string]struct{}
+
+	// counter holds the next value of the `{extra.counter}` placeholder.
+	// It is a pointer, as ServeHTTP operates on a copy of ExtraPlaceholders.
+	counter *atomic.Uint64
+
+	// seq holds the next value of the `{extra.seq.next}` placeholder.
+	seq *atomic.Int64
+
+	// activeRequests holds the number of requests currently being handled by this instance.
+	activeRequests *atomic.Int64
+
+	// rng is the per-instance random source for the `{extra.rand.*}` placeholders.
+	// It is backed by a lockedSource, as ServeHTTP is called concurrently.
+	rng *rand.Rand
+
+	// randChoiceTotal is the sum of all RandChoices weights, computed during provisioning.
+	randChoiceTotal int
+
+	// customKeys holds the keys of Custom in the order in which they are resolved, computed during provisioning.
+	customKeys []string
+
+	// timeZoneLocation is the loaded location of the configured TimeZone.
+	timeZoneLocation *time.Location
+
+	// forceTimeLocation is the loaded location of the configured ForceTimeLocation.
+	forceTimeLocation *time.Location
+
+	// businessHoursStart and businessHoursEnd are the parsed business hours in minutes since midnight.
+	// businessHoursSet reports whether business hours are configured.
+	businessHoursStart int
+	businessHoursEnd   int
+	businessHoursSet   bool
+
+	// loadavgCache caches the load average reading for LoadavgCacheTTL.
+	loadavgCache *ttlCache[*load.AvgStat]
+
+	// diskCache caches the disk usage readings per resolved disk path for DiskCacheTTL.
+	diskCache *ttlCacheMap[*disk.UsageStat]
+
+	// netCache caches the network I/O counters reading for NetCacheTTL.
+	netCache *ttlCache[psnet.IOCountersStat]
+
+	// fileCaches holds the cache for each file in ReadFiles, keyed by alias.
+	fileCaches map[string]*fileCache
+
+	// kernelStatsSampler caches the kernel activity counters and the available entropy for kernelStatsCacheTTL.
+	kernelStatsSampler *kernelStatsSampler
+
+	// cgroupCPUQuota holds the CPU limit of the cgroup of the Caddy process, determined once during provisioning.
+	// It is empty if the limit could not be determined.
+	cgroupCPUQuota string
+
+	// virtualizationSystem and virtualizationRole hold the detected virtualization (e.g., docker and guest),
+	// determined once during provisioning. They are empty on bare metal or if the detection failed.
+	virtualizationSystem string
+	virtualizationRole   string
+
+	// processCountCache caches the number of processes on the system for processCountCacheTTL.
+	processCountCache *ttlCache[int]
+
+	// memStatsCache caches the Go runtime memory statistics for memStatsCacheTTL.
+	memStatsCache *ttlCache[*runtime.MemStats]
+
+	// usersCache caches the number of logged-in users for usersCacheTTL.
+	usersCache *ttlCache[int]
+
+	// usersErrOnce ensures that the failure to retrieve the logged-in users, which is expected
+	// e.g. in containers without /var/run/utmp, is only logged once.
+	usersErrOnce *sync.Once
+
+	// sensorsCache caches the sensor temperatures reading for SensorsCacheTTL.
+	sensorsCache *ttlCache[[]sensors.TemperatureStat]
+
+	// sensorsEmptyOnce ensures that the absence of temperature sensors is only logged once.
+	sensorsEmptyOnce *sync.Once
+
+	// processSampler holds the handle of the Caddy process, created once during provisioning.
+	// It is nil if the process handle could not be created.
+	processSampler *processSampler
+
+	// processStatsCache caches the resource usage reading of the Caddy process for cpuCacheTTL.
+	processStatsCache *ttlCache[processStats]
+
+	// bootTime is the system boot time, retrieved once during provisioning.
+	// It is zero if the boot time could not be retrieved.
+	bootTime time.Time
+
+	// startTime is the time the handler was provisioned, i.e. Caddy was started or its config was reloaded.
+	startTime time.Time
+
+	// timestampFormat is the format for the `{extra.hostinfo.boottime}`, `{extra.process.start_time}` and
+	// `{extra.caddy.last_reload}` placeholders: TimeFormatCustom if configured, RFC3339 otherwise.
+	timestampFormat string
+
+	// buildGoVersion and buildMainPath hold the static build information of the Caddy binary,
+	// retrieved once during provisioning. They are empty if the build information is not available.
+	buildGoVersion string
+	buildMainPath  string
+
+	// instanceID holds the persistent UUID of this Caddy instance, retrieved once during provisioning.
+	// It is empty if the instance ID could not be retrieved.
+	instanceID string
+
+	// hostInfo holds the static host information, retrieved once during provisioning.
+	// It is nil if the host information could not be retrieved.
+	hostInfo *host.InfoStat
+
+	// localIP holds the primary outbound IP address, determined once during provisioning.
+	localIP string
+
+	// refresher periodically retrieves the host metrics in the background if RefreshInterval is configured.
+	refresher *refresher
+
+	// cpuSampler keeps the previous CPU times sample for the non-blocking utilization calculation.
+	cpuSampler *cpuSampler
+
+	// cpuCache caches the CPU utilization reading for cpuCacheTTL.
+	cpuCache *ttlCache[cpuUtilization]
+
+	// cpuCount holds the number of logical CPU cores, determined once during provisioning.
+	cpuCount int
+
 	// logger provides structured logging for the plugin's internal operations.
 	logger *zap.Logger
 }
 
+// RandChoice is a value for the `{extra.rand.choice}` placeholder together with its relative weight.
+type RandChoice struct {
+	// Value is the value set for the `{extra.rand.choice}` placeholder if this choice is picked.
+	Value string `json:"value"`
+
+	// Weight is the relative weight of this choice. It must be a positive integer.
+	Weight int `json:"weight"`
+}
+
 // CaddyModule returns the module information required by Caddy to register the plugin.
 func (ExtraPlaceholders) CaddyModule() caddy.ModuleInfo {
 	return caddy.ModuleInfo{
@@ -108,21 +676,290 @@ func (ExtraPlaceholders) CaddyModule() caddy.ModuleInfo {
 // Provision sets up the module. It is called once the module is instantiated.
 func (e *ExtraPlaceholders) Provision(ctx caddy.Context) error {
 	e.logger = ctx.Logger()
+	provisionCount.Add(1)
 
 	// Set default values if not configured
-	if e.RandIntMin == 0 && e.RandIntMax == 0 {
-		e.RandIntMin = 0
-		e.RandIntMax = 100
+	if e.Prefix == "" {
+		e.Prefix = defaultPrefix
+	}
+	e.randIntMin, e.randIntMax = 0, 100
+	if e.RandIntMin != nil {
+		e.randIntMin = *e.RandIntMin
+	}
+	if e.RandIntMax != nil {
+		e.randIntMax = *e.RandIntMax
+	}
+	e.randIntPadWidth = len(strconv.Itoa(e.randIntMax))
+	if e.RandNormalStddev == 0 {
+		e.RandNormalStddev = 1
+	}
+	e.randBoolProbability = defaultRandBoolProbability
+	if e.RandBoolProbability != nil {
+		e.randBoolProbability = *e.RandBoolProbability
+	}
+	if e.RandStringLength == 0 {
+		e.RandStringLength = defaultRandStringLength
+	}
+	if e.RandStringAlphabet == "" {
+		e.RandStringAlphabet = defaultRandStringAlphabet
+	}
+	e.startTime = time.Now()
+	e.timestampFormat = e.TimeFormatCustom
+	if e.timestampFormat == "" {
+		e.timestampFormat = time.RFC3339
+	}
+	if e.RandHexBytes == 0 {
+		e.RandHexBytes = defaultRandHexBytes
 	}
 	if e.TimeFormatCustom == "" {
 		e.TimeFormatCustom = defaultTimeFormatCustom
 	}
+	if e.DiskPath == "" {
+		e.DiskPath = defaultDiskPath
+	}
+	if e.LoadavgCacheTTL == 0 {
+		e.LoadavgCacheTTL = caddy.Duration(defaultLoadavgCacheTTL)
+	}
+	if e.DiskCacheTTL == 0 {
+		e.DiskCacheTTL = caddy.Duration(defaultDiskCacheTTL)
+	}
+	if e.NetCacheTTL == 0 {
+		e.NetCacheTTL = caddy.Duration(defaultNetCacheTTL)
+	}
+	if e.FileCacheTTL == 0 {
+		e.FileCacheTTL = caddy.Duration(defaultFileCacheTTL)
+	}
+	if e.SensorsCacheTTL == 0 {
+		e.SensorsCacheTTL = caddy.Duration(defaultSensorsCacheTTL)
+	}
+
+	if e.TimeZone != "" {
+		loc, err := time.LoadLocation(e.TimeZone)
+		if err != nil {
+			return fmt.Errorf("invalid time_zone %q: %v", e.TimeZone, err)
+		}
+		e.timeZoneLocation = loc
+	}
+	if e.ForceTimeLocation != "" {
+		loc, err := time.LoadLocation(e.ForceTimeLocation)
+		if err != nil {
+			return fmt.Errorf("invalid force_time_location %q: %v", e.ForceTimeLocation, err)
+		}
+		e.forceTimeLocation = loc
+	}
+	if e.BusinessHoursStart != "" || e.BusinessHoursEnd != "" {
+		start, err := parseClockTime(e.BusinessHoursStart)
+		if err != nil {
+			return fmt.Errorf("invalid business_hours_start %q: %v", e.BusinessHoursStart, err)
+		}
+		end, err := parseClockTime(e.BusinessHoursEnd)
+		if err != nil {
+			return fmt.Errorf("invalid business_hours_end %q: %v", e.BusinessHoursEnd, err)
+		}
+		e.businessHoursStart, e.businessHoursEnd, e.businessHoursSet = start, end, true
+	}
+
+	if len(e.Placeholders) > 0 {
+		e.enabledGroups = make(map[string]struct{}, len(e.Placeholders))
+		for _, group := range e.Placeholders {
+			e.enabledGroups[group] = struct{}{}
+		}
+	}
+
+	e.randChoiceTotal = 0
+	for _, choice := range e.RandChoices {
+		e.randChoiceTotal += choice.Weight
+	}
+
+	customKeys, err := e.customOrder()
+	if err != nil {
+		return err
+	}
+	e.customKeys = customKeys
+
+	e.counter = new(atomic.Uint64)
+	e.counter.Store(e.CounterStart)
+	if e.SeqStep == 0 {
+		e.SeqStep = 1
+	}
+	e.seq = new(atomic.Int64)
+	e.seq.Store(e.SeqStart)
+	e.activeRequests = new(atomic.Int64)
+
+	// Use a dedicated random source per instance, seeded with the configured seed if any.
+	seed := e.RandSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	e.rng = rand.New(newLockedSource(seed))
+
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		e.buildGoVersion = buildInfo.GoVersion
+		e.buildMainPath = buildInfo.Main.Path
+	}
+
+	// Only retrieve the instance ID if needed, as Caddy creates the file holding it if missing.
+	if e.groupEnabled("caddy") {
+		if instanceID, err := caddy.InstanceID(); err == nil {
+			e.instanceID = instanceID.String()
+		} else {
+			e.logger.Warn("Failed to retrieve Caddy instance ID", zap.Error(err))
+		}
+	}
+
+	if !e.DisableHostinfoPlaceholders {
+		// Retrieve the boot time once, so the current uptime can be derived per request without a syscall.
+		if bootTime, err := host.BootTime(); err == nil {
+			e.bootTime = time.Unix(int64(bootTime), 0)
+		} else {
+			e.logger.Warn("Failed to retrieve system boot time", zap.Error(err))
+		}
+
+		// The host information is static, so it is retrieved only once as well.
+		if hostInfo, err := host.Info(); err == nil {
+			e.hostInfo = hostInfo
+		} else {
+			e.logger.Warn("Failed to retrieve host information", zap.Error(err))
+		}
+
+		if localIP, err := outboundIP(); err == nil {
+			e.localIP = localIP
+		} else {
+			e.logger.Warn("Failed to determine the primary outbound IP address", zap.Error(err))
+		}
+
+		e.usersCache = newTTLCache[int](usersCacheTTL)
+		e.usersErrOnce = new(sync.Once)
+	}
+
+	if !e.DisableGoPlaceholders {
+		e.memStatsCache = newTTLCache[*runtime.MemStats](memStatsCacheTTL)
+	}
+
+	// The cgroup CPU limit is considered static, so it is determined only once.
+	if quota, err := cgroupCPUQuota(); err == nil {
+		e.cgroupCPUQuota = quota
+	} else {
+		e.logger.Debug("Failed to determine the cgroup CPU limit", zap.Error(err))
+	}
+
+	// The virtualization does not change while Caddy is running, so it is detected only once as well.
+	if system, role, err := host.Virtualization(); err == nil {
+		e.virtualizationSystem = system
+		e.virtualizationRole = role
+	} else {
+		e.logger.Debug("Failed to detect the virtualization", zap.Error(err))
+	}
+
+	e.kernelStatsSampler = &kernelStatsSampler{cache: newTTLCache[kernelStats](kernelStatsCacheTTL)}
+
+	if !e.DisableLoadavgPlaceholders {
+		e.loadavgCache = newTTLCache[*load.AvgStat](time.Duration(e.LoadavgCacheTTL))
+	}
+
+	e.fileCaches = make(map[string]*fileCache, len(e.ReadFiles))
+	for alias, path := range e.ReadFiles {
+		e.fileCaches[alias] = newFileCache(path, time.Duration(e.FileCacheTTL), e.logger)
+	}
+
+	e.diskCache = newTTLCacheMap[*disk.UsageStat](time.Duration(e.DiskCacheTTL), maxDiskCacheEntries)
+
+	if !e.DisableNetPlaceholders {
+		e.netCache = newTTLCache[psnet.IOCountersStat](time.Duration(e.NetCacheTTL))
+	}
+
+	if !e.DisableSensorsPlaceholders {
+		e.sensorsCache = newTTLCache[[]sensors.TemperatureStat](time.Duration(e.SensorsCacheTTL))
+		e.sensorsEmptyOnce = new(sync.Once)
+	}
+
+	if !e.DisableProcessPlaceholders {
+		e.processCountCache = newTTLCache[int](processCountCacheTTL)
+
+		if proc, err := process.NewProcess(int32(os.Getpid())); err == nil {
+			e.processSampler = &processSampler{proc: proc}
+			e.processStatsCache = newTTLCache[processStats](cpuCacheTTL)
+			// Take an initial sample, so the first request reports the utilization since provisioning.
+			_, _ = e.processSampler.cpuPercent()
+		} else {
+			e.logger.Warn("Failed to create the handle of the Caddy process", zap.Error(err))
+		}
+	}
+
+	if !e.DisableCPUPlaceholders {
+		e.cpuSampler = &cpuSampler{}
+		e.cpuCache = newTTLCache[cpuUtilization](cpuCacheTTL)
+		// Take an initial sample, so the first request already reports the utilization since provisioning.
+		_, _, _ = e.cpuSampler.percent()
+
+		cpuCount, err := cpu.Counts(true)
+		if err != nil {
+			e.logger.Warn("Failed to determine the number of logical CPUs", zap.Error(err))
+		}
+		e.cpuCount = cpuCount
+	}
+
+	if e.RefreshInterval > 0 {
+		e.refresher = e.startRefresher(time.Duration(e.RefreshInterval))
+	}
 
 	// Log the chosen configuration values
 	e.logger.Info("ExtraPlaceholders plugin configured",
-		zap.Int("RandIntMin", e.RandIntMin),
-		zap.Int("RandIntMax", e.RandIntMax),
+		zap.String("Prefix", e.Prefix),
+		zap.Int("RandIntMin", e.randIntMin),
+		zap.Int("RandIntMax", e.randIntMax),
+		zap.Int("RandFloatPrecision", e.RandFloatPrecision),
+		zap.Float64("RandNormalMean", e.RandNormalMean),
+		zap.Float64("RandNormalStddev", e.RandNormalStddev),
+		zap.Int("RandStringLength", e.RandStringLength),
+		zap.String("RandStringAlphabet", e.RandStringAlphabet),
+		zap.Int("RandHexBytes", e.RandHexBytes),
+		zap.Int64("RandSeed", e.RandSeed),
+		zap.Bool("RandCrypto", e.RandCrypto),
+		zap.Any("RandChoices", e.RandChoices),
+		zap.Strings("RandList", e.RandList),
+		zap.Float64("RandBoolProbability", e.randBoolProbability),
+		zap.Duration("RandDurationMin", time.Duration(e.RandDurationMin)),
+		zap.Duration("RandDurationMax", time.Duration(e.RandDurationMax)),
 		zap.String("TimeFormatCustom", e.TimeFormatCustom),
+		zap.Any("TimeFormatsCustom", e.TimeFormatsCustom),
+		zap.String("TimeZone", e.TimeZone),
+		zap.String("ForceTimeLocation", e.ForceTimeLocation),
+		zap.String("BusinessHoursStart", e.BusinessHoursStart),
+		zap.String("BusinessHoursEnd", e.BusinessHoursEnd),
+		zap.String("DiskPath", e.DiskPath),
+		zap.Any("DiskPaths", e.DiskPaths),
+		zap.Duration("DiskCacheTTL", time.Duration(e.DiskCacheTTL)),
+		zap.Int("GoroutineSoftLimit", e.GoroutineSoftLimit),
+		zap.Float64("LoadavgThreshold", e.LoadavgThreshold),
+		zap.Float64("LoadavgMax", e.LoadavgMax),
+		zap.Int("LoadavgPrecision", e.LoadavgPrecision),
+		zap.Duration("RefreshInterval", time.Duration(e.RefreshInterval)),
+		zap.Duration("NetCacheTTL", time.Duration(e.NetCacheTTL)),
+		zap.Duration("SensorsCacheTTL", time.Duration(e.SensorsCacheTTL)),
+		zap.Int("HashBuckets", e.HashBuckets),
+		zap.String("NetInterface", e.NetInterface),
+		zap.Uint64("CounterStart", e.CounterStart),
+		zap.Int64("SeqStart", e.SeqStart),
+		zap.Int64("SeqStep", e.SeqStep),
+		zap.String("Region", e.Region),
+		zap.Strings("EnvAllow", e.EnvAllow),
+		zap.Any("ReadFiles", e.ReadFiles),
+		zap.Duration("FileCacheTTL", time.Duration(e.FileCacheTTL)),
+		zap.Bool("HumanizeBytes", e.HumanizeBytes),
+		zap.Any("Custom", e.Custom),
+		zap.Strings("Placeholders", e.Placeholders),
+		zap.Duration("LoadavgCacheTTL", time.Duration(e.LoadavgCacheTTL)),
+		zap.Bool("DisableLoadavgPlaceholders", e.DisableLoadavgPlaceholders),
+		zap.Bool("DisableHostinfoPlaceholders", e.DisableHostinfoPlaceholders),
+		zap.Bool("DisableCPUPlaceholders", e.DisableCPUPlaceholders),
+		zap.Bool("DisableMemPlaceholders", e.DisableMemPlaceholders),
+		zap.Bool("DisableNetPlaceholders", e.DisableNetPlaceholders),
+		zap.Bool("DisableProcessPlaceholders", e.DisableProcessPlaceholders),
+		zap.Bool("DisableTimePlaceholders", e.DisableTimePlaceholders),
+		zap.Bool("DisableGoPlaceholders", e.DisableGoPlaceholders),
+		zap.Bool("DisableCaddyVersionPlaceholders", e.DisableCaddyVersionPlaceholders),
+		zap.Bool("DisableSensorsPlaceholders", e.DisableSensorsPlaceholders),
 	)
 
 	return nil
@@ -130,33 +967,259 @@ func (e *ExtraPlaceholders) Provision(ctx caddy.Context) error {
 
 // Validate ensures the configuration is correct.
 func (e *ExtraPlaceholders) Validate() error {
-	if e.RandIntMax <= e.RandIntMin {
-		return fmt.Errorf("invalid configuration: RandIntMax (%d) must be greater than RandIntMin (%d)", e.RandIntMax, e.RandIntMin)
+	if !prefixPattern.MatchString(e.Prefix) {
+		return fmt.Errorf("invalid configuration: Prefix (%q) must match %s", e.Prefix, prefixPattern)
+	}
+	if e.randIntMax < e.randIntMin {
+		return fmt.Errorf("invalid configuration: RandIntMax (%d) must not be less than RandIntMin (%d)", e.randIntMax, e.randIntMin)
+	}
+	if e.RandFloatPrecision < 0 {
+		return fmt.Errorf("invalid configuration: RandFloatPrecision (%d) must not be negative", e.RandFloatPrecision)
+	}
+	if e.RandNormalStddev < 0 {
+		return fmt.Errorf("invalid configuration: RandNormalStddev (%g) must not be negative", e.RandNormalStddev)
+	}
+	if !(e.randBoolProbability >= 0 && e.randBoolProbability <= 1) {
+		return fmt.Errorf("invalid configuration: RandBoolProbability (%g) must be between 0 and 1", e.randBoolProbability)
+	}
+	if e.RandStringLength < 0 {
+		return fmt.Errorf("invalid configuration: RandStringLength (%d) must not be negative", e.RandStringLength)
+	}
+	if e.RandHexBytes < 0 {
+		return fmt.Errorf("invalid configuration: RandHexBytes (%d) must not be negative", e.RandHexBytes)
+	}
+	if (e.RandDurationMin != 0 || e.RandDurationMax != 0) && e.RandDurationMax <= e.RandDurationMin {
+		return fmt.Errorf("invalid configuration: RandDurationMax (%s) must be greater than RandDurationMin (%s)",
+			time.Duration(e.RandDurationMax), time.Duration(e.RandDurationMin))
+	}
+	if e.RandDurationMin < 0 {
+		return fmt.Errorf("invalid configuration: RandDurationMin (%s) must not be negative", time.Duration(e.RandDurationMin))
+	}
+	for _, choice := range e.RandChoices {
+		if choice.Weight <= 0 {
+			return fmt.Errorf("invalid configuration: weight (%d) of rand_choice value %q must be a positive integer", choice.Weight, choice.Value)
+		}
+	}
+	if !validDiskPath(e.DiskPath) {
+		return fmt.Errorf("invalid configuration: DiskPath (%q) must be a non-empty absolute path, or start with one before the first placeholder", e.DiskPath)
+	}
+	for alias, path := range e.DiskPaths {
+		if alias == "" {
+			return fmt.Errorf("invalid configuration: DiskPaths aliases must not be empty")
+		}
+		if !validDiskPath(path) {
+			return fmt.Errorf("invalid configuration: path (%q) of disk_path alias %q must be a non-empty absolute path, or start with one before the first placeholder", path, alias)
+		}
+	}
+	if e.DiskCacheTTL < 0 {
+		return fmt.Errorf("invalid configuration: DiskCacheTTL (%s) must not be negative", time.Duration(e.DiskCacheTTL))
+	}
+	for _, group := range e.Placeholders {
+		if !slices.Contains(placeholderGroups, group) {
+			return fmt.Errorf("invalid configuration: unknown placeholder group %q, must be one of %v", group, placeholderGroups)
+		}
+	}
+	if e.LoadavgCacheTTL < 0 {
+		return fmt.Errorf("invalid configuration: LoadavgCacheTTL (%s) must not be negative", time.Duration(e.LoadavgCacheTTL))
+	}
+	if e.GoroutineSoftLimit < 0 {
+		return fmt.Errorf("invalid configuration: GoroutineSoftLimit (%d) must not be negative", e.GoroutineSoftLimit)
+	}
+	if e.LoadavgThreshold < 0 {
+		return fmt.Errorf("invalid configuration: LoadavgThreshold (%g) must not be negative", e.LoadavgThreshold)
+	}
+	if e.LoadavgMax < 0 {
+		return fmt.Errorf("invalid configuration: LoadavgMax (%g) must not be negative", e.LoadavgMax)
+	}
+	if e.LoadavgPrecision < 0 {
+		return fmt.Errorf("invalid configuration: LoadavgPrecision (%d) must not be negative", e.LoadavgPrecision)
+	}
+	if e.RefreshInterval < 0 {
+		return fmt.Errorf("invalid configuration: RefreshInterval (%s) must not be negative", time.Duration(e.RefreshInterval))
+	}
+	if e.NetCacheTTL < 0 {
+		return fmt.Errorf("invalid configuration: NetCacheTTL (%s) must not be negative", time.Duration(e.NetCacheTTL))
+	}
+	if slices.Contains(e.EnvAllow, "") {
+		return fmt.Errorf("invalid configuration: EnvAllow names must not be empty")
+	}
+	if _, exists := e.ReadFiles[""]; exists {
+		return fmt.Errorf("invalid configuration: ReadFiles aliases must not be empty")
+	}
+	if e.FileCacheTTL < 0 {
+		return fmt.Errorf("invalid configuration: FileCacheTTL (%s) must not be negative", time.Duration(e.FileCacheTTL))
+	}
+	if e.HashBuckets < 0 {
+		return fmt.Errorf("invalid configuration: HashBuckets (%d) must not be negative", e.HashBuckets)
+	}
+	if e.SensorsCacheTTL < 0 {
+		return fmt.Errorf("invalid configuration: SensorsCacheTTL (%s) must not be negative", time.Duration(e.SensorsCacheTTL))
+	}
+	if _, exists := e.Custom[""]; exists {
+		return fmt.Errorf("invalid configuration: custom placeholder keys must not be empty")
+	}
+	if e.businessHoursSet && e.businessHoursEnd <= e.businessHoursStart {
+		return fmt.Errorf("invalid configuration: BusinessHoursEnd (%q) must be after BusinessHoursStart (%q)", e.BusinessHoursEnd, e.BusinessHoursStart)
+	}
+
+	// A malformed time format is not an error, as any string is a valid layout, but it is likely a typo.
+	if reason := suspiciousTimeFormat(e.TimeFormatCustom); reason != "" {
+		e.logger.Warn("Suspicious custom time format", zap.String("format", e.TimeFormatCustom), zap.String("reason", reason))
+	}
+	for name, format := range e.TimeFormatsCustom {
+		if reason := suspiciousTimeFormat(format); reason != "" {
+			e.logger.Warn("Suspicious custom time format", zap.String("name", name), zap.String("format", format), zap.String("reason", reason))
+		}
+	}
+	return nil
+}
+
+// Cleanup stops the background refresher, if running, when the configuration is unloaded,
+// e.g. on a config reload. The caches and samplers are left in place, since requests still
+// in flight may use them, and are released by the garbage collector with the handler.
+func (e *ExtraPlaceholders) Cleanup() error {
+	if e.refresher != nil {
+		e.refresher.stopAndWait()
 	}
 	return nil
 }
 
+// key returns the placeholder key for the given name below the configured prefix,
+// e.g. "extra.rand.int" for "rand.int".
+func (e ExtraPlaceholders) key(name string) string {
+	return e.Prefix + "." + name
+}
+
+// groupEnabled reports whether the given placeholder group is selected via the `placeholders` directive.
+// All groups are enabled if the directive is omitted.
+func (e ExtraPlaceholders) groupEnabled(group string) bool {
+	if e.enabledGroups == nil {
+		return true
+	}
+	_, ok := e.enabledGroups[group]
+	return ok
+}
+
 // ServeHTTP adds new placeholders and passes the request to the next handler in the chain.
 func (e ExtraPlaceholders) ServeHTTP(w http.ResponseWriter, r *http.Request, next caddyhttp.Handler) error {
+	handlerStart := time.Now()
+
+	e.activeRequests.Add(1)
+	defer e.activeRequests.Add(-1)
+
 	// Retrieve the replacer from the request context.
 	repl, ok := r.Context().Value(caddy.ReplacerCtxKey).(*caddy.Replacer)
 	if !ok {
 		return caddyhttp.Error(http.StatusInternalServerError, nil)
 	}
 
-	e.setCaddyPlaceholders(repl)
-	e.setRandPlaceholders(repl)
-	e.setLoadavgPlaceholders(repl)
-	e.setHostinfoPlaceholders(repl)
+	// Set the monotonic clock reading first, as close to the handler entry as possible.
+	if e.groupEnabled("time") && !e.DisableTimePlaceholders {
+		repl.Set(e.key("time.mono"), handlerStart.Sub(monoStart).Nanoseconds())
+	}
+
+	if e.groupEnabled("caddy") {
+		e.setCaddyPlaceholders(repl)
+	}
+	if e.groupEnabled("rand") {
+		e.setRandPlaceholders(repl)
+
+		// Echo the effective bounds of the random integer for debugging
+		repl.Set(e.key("config.rand_int_min"), e.randIntMin)
+		repl.Set(e.key("config.rand_int_max"), e.randIntMax)
+	}
+	if e.groupEnabled("loadavg") && !e.DisableLoadavgPlaceholders {
+		e.setLoadavgPlaceholders(repl)
+	}
+	if e.groupEnabled("hostinfo") && !e.DisableHostinfoPlaceholders {
+		e.setHostinfoPlaceholders(repl)
+	}
+	if e.groupEnabled("host") {
+		e.setHostPlaceholders(repl)
+	}
+	if e.groupEnabled("cpu") && !e.DisableCPUPlaceholders {
+		e.setCPUPlaceholders(repl)
+	}
+	if e.groupEnabled("mem") && !e.DisableMemPlaceholders {
+		e.setMemPlaceholders(repl)
+	}
+	if e.groupEnabled("net") && !e.DisableNetPlaceholders {
+		e.setNetPlaceholders(repl)
+	}
+	if e.groupEnabled("sensors") && !e.DisableSensorsPlaceholders {
+		e.setSensorsPlaceholders(repl)
+	}
+	if e.groupEnabled("process") && !e.DisableProcessPlaceholders {
+		e.setProcessPlaceholders(repl)
+	}
+	if e.groupEnabled("disk") {
+		e.setDiskPlaceholders(repl)
+	}
+	if e.groupEnabled("go") && !e.DisableGoPlaceholders {
+		e.setGoPlaceholders(repl)
+	}
+
+	if e.groupEnabled("time") && !e.DisableTimePlaceholders {
+		now := time.Now()
 
-	// Set time placeholders for server's local time
-	e.setTimePlaceholders(repl, time.Now(), false)
+		// Set time placeholders for server's local time, or the forced location if configured
+		local := now
+		if e.forceTimeLocation != nil {
+			local = now.In(e.forceTimeLocation)
+		}
+		e.setTimePlaceholders(repl, local, e.key("time.now"))
 
-	// Set time placeholders for UTC time
-	e.setTimePlaceholders(repl, time.Now().UTC(), true)
+		// Set time placeholders for UTC time
+		e.setTimePlaceholders(repl, now.UTC(), e.key("time.now.utc"))
+
+		// Set time placeholders for the configured timezone
+		if e.timeZoneLocation != nil {
+			e.setTimePlaceholders(repl, now.In(e.timeZoneLocation), e.key("time.now.tz"))
+		}
+	}
+
+	if e.groupEnabled("request") {
+		e.setRequestPlaceholders(repl, r, handlerStart)
+	}
+	if e.groupEnabled("hash") {
+		e.setHashPlaceholders(repl, r)
+	}
+	if e.groupEnabled("trace") {
+		e.setTracePlaceholders(repl)
+	}
+	if e.groupEnabled("file") {
+		e.setFilePlaceholders(repl)
+	}
+
+	// Set the request counter placeholder
+	if e.groupEnabled("counter") {
+		repl.Set(e.key("counter"), e.counter.Add(1)-1)
+	}
+
+	// Set the sequence placeholder
+	if e.groupEnabled("seq") {
+		repl.Set(e.key("seq.next"), e.seq.Add(e.SeqStep)-e.SeqStep)
+	}
+
+	// Set the region placeholder, if configured
+	if e.groupEnabled("node") && e.Region != "" {
+		repl.Set(e.key("node.region"), e.Region)
+	}
+
+	// Set the placeholders for the allowed environment variables
+	if e.groupEnabled("env") {
+		for _, name := range e.EnvAllow {
+			repl.Set(e.key("env."+name), os.Getenv(name))
+		}
+	}
 
 	// Set newline placeholder
-	repl.Set("extra.newline", "\n")
+	repl.Set(e.key("newline"), "\n")
+
+	// Set the custom placeholders last, so that their values can reference all other placeholders
+	if e.groupEnabled("custom") {
+		e.setCustomPlaceholders(repl)
+	}
 
 	// Call the next handler in the chain.
 	return next.ServeHTTP(w, r)
@@ -167,5 +1230,6 @@ var (
 	_ caddy.Module                = (*ExtraPlaceholders)(nil)
 	_ caddy.Provisioner           = (*ExtraPlaceholders)(nil)
 	_ caddy.Validator             = (*ExtraPlaceholders)(nil)
+	_ caddy.CleanerUpper          = (*ExtraPlaceholders)(nil)
 	_ caddyhttp.MiddlewareHandler = (*ExtraPlaceholders)(nil)
 )
@@ -0,0 +1,240 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/caddyserver/caddy/v2"
+	"github.com/caddyserver/caddy/v2/caddyconfig/caddyfile"
+	"github.com/caddyserver/caddy/v2/modules/caddyhttp"
+)
+
+// newTestHandler parses the given Caddyfile configuration and returns the provisioned and validated handler.
+// The handler is cleaned up when the test finishes.
+func newTestHandler(tb testing.TB, config string) *ExtraPlaceholders {
+	tb.Helper()
+
+	e := new(ExtraPlaceholders)
+	if err := e.UnmarshalCaddyfile(caddyfile.NewTestDispenser(config)); err != nil {
+		tb.Fatalf("UnmarshalCaddyfile() error = %v", err)
+	}
+	if err := provisionTestHandler(tb, e); err != nil {
+		tb.Fatal(err)
+	}
+	return e
+}
+
+// provisionTestHandler provisions and validates the given handler and returns the first error.
+// The handler is cleaned up when the test finishes. The Caddy data directory, e.g. for the
+// instance ID, is redirected to a temporary directory, so the tests do not touch $HOME.
+func provisionTestHandler(tb testing.TB, e *ExtraPlaceholders) error {
+	tb.Helper()
+
+	tb.Setenv("XDG_DATA_HOME", tb.TempDir())
+
+	ctx, cancel := caddy.NewContext(caddy.Context{Context: context.Background()})
+	tb.Cleanup(cancel)
+	if err := e.Provision(ctx); err != nil {
+		return fmt.Errorf("Provision() error = %v", err)
+	}
+	tb.Cleanup(func() { _ = e.Cleanup() })
+	if err := e.Validate(); err != nil {
+		return fmt.Errorf("Validate() error = %v", err)
+	}
+	return nil
+}
+
+// serveTestRequest passes a request through the handler and returns the replacer with the placeholders.
+// It may be called from other goroutines than the test, so it reports errors without stopping the test.
+func serveTestRequest(tb testing.TB, e *ExtraPlaceholders) *caddy.Replacer {
+	tb.Helper()
+
+	repl := caddy.NewReplacer()
+	r := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+	r = r.WithContext(context.WithValue(r.Context(), caddy.ReplacerCtxKey, repl))
+	next := caddyhttp.HandlerFunc(func(http.ResponseWriter, *http.Request) error { return nil })
+	if err := e.ServeHTTP(httptest.NewRecorder(), r, next); err != nil {
+		tb.Errorf("ServeHTTP() error = %v", err)
+	}
+	return repl
+}
+
+// TestServeHTTPConcurrent serves requests concurrently, so that data races on the shared state
+// of a handler, e.g. its random source, are reported when run with -race.
+func TestServeHTTPConcurrent(t *testing.T) {
+	e := newTestHandler(t, `extra_placeholders {
+		placeholders rand counter
+		rand_int 1 6
+		rand_seed 42
+	}`)
+
+	const requests = 100
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	results := make(chan *caddy.Replacer, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			results <- serveTestRequest(t, e)
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(results)
+
+	counters := make(map[any]bool, requests)
+	for repl := range results {
+		v, _ := repl.Get("extra.rand.int")
+		if i, ok := v.(int); !ok || i < 1 || i > 6 {
+			t.Errorf("extra.rand.int = %v, want an int in [1, 6]", v)
+		}
+		counter, _ := repl.Get("extra.counter")
+		if counters[counter] {
+			t.Errorf("extra.counter = %v was set for more than one request", counter)
+		}
+		counters[counter] = true
+	}
+}
+
+// TestPlaceholderGroups verifies that only the placeholders of the selected groups are set,
+// apart from `{extra.newline}`.
+func TestPlaceholderGroups(t *testing.T) {
+	t.Setenv("EXTRA_PLACEHOLDERS_TEST", "value")
+	e := newTestHandler(t, `extra_placeholders {
+		placeholders rand
+		region eu-central
+		env_allow EXTRA_PLACEHOLDERS_TEST
+		set greeting hello
+	}`)
+
+	repl := serveTestRequest(t, e)
+	for _, key := range []string{"extra.counter", "extra.seq.next", "extra.node.region", "extra.env.EXTRA_PLACEHOLDERS_TEST", "extra.custom.greeting"} {
+		if v, ok := repl.Get(key); ok {
+			t.Errorf("%s = %v, want unset", key, v)
+		}
+	}
+	for _, key := range []string{"extra.rand.int", "extra.newline"} {
+		if _, ok := repl.Get(key); !ok {
+			t.Errorf("%s is not set", key)
+		}
+	}
+}
+
+// TestServeHTTPAfterCleanup verifies that a request still in flight when the configuration is
+// unloaded does not panic on the caches and samplers of the handler.
+func TestServeHTTPAfterCleanup(t *testing.T) {
+	e := newTestHandler(t, `extra_placeholders`)
+	if err := e.Cleanup(); err != nil {
+		t.Fatalf("Cleanup() error = %v", err)
+	}
+
+	repl := serveTestRequest(t, e)
+	if _, ok := repl.Get("extra.loadavg.1"); !ok {
+		t.Error("extra.loadavg.1 is not set after Cleanup()")
+	}
+}
+
+// BenchmarkServeHTTP shows the allocations saved per request by disable_time_placeholders.
+func BenchmarkServeHTTP(b *testing.B) {
+	for _, bm := range []struct {
+		name   string
+		config string
+	}{
+		{"time_enabled", `extra_placeholders`},
+		{"time_disabled", `extra_placeholders {
+			disable_time_placeholders
+		}`},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			e := newTestHandler(b, bm.config)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				serveTestRequest(b, e)
+			}
+		})
+	}
+}
+
+func TestProvisionRandIntRange(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+
+	tests := []struct {
+		name             string
+		min, max         *int
+		wantMin, wantMax int
+		wantErr          bool
+	}{
+		{name: "unset", wantMin: 0, wantMax: 100},
+		{name: "0 5", min: intPtr(0), max: intPtr(5), wantMin: 0, wantMax: 5},
+		{name: "0 0", min: intPtr(0), max: intPtr(0), wantMin: 0, wantMax: 0},
+		{name: "-3 -3", min: intPtr(-3), max: intPtr(-3), wantMin: -3, wantMax: -3},
+		{name: "only max 0", max: intPtr(0), wantMin: 0, wantMax: 0},
+		{name: "5 3", min: intPtr(5), max: intPtr(3), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &ExtraPlaceholders{RandIntMin: tt.min, RandIntMax: tt.max}
+			err := provisionTestHandler(t, e)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("want an error for a maximum less than the minimum")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e.randIntMin != tt.wantMin || e.randIntMax != tt.wantMax {
+				t.Fatalf("effective range = [%d, %d], want [%d, %d]", e.randIntMin, e.randIntMax, tt.wantMin, tt.wantMax)
+			}
+
+			for i := 0; i < 20; i++ {
+				v, _ := serveTestRequest(t, e).Get("extra.rand.int")
+				if n, ok := v.(int); !ok || n < tt.wantMin || n > tt.wantMax {
+					t.Fatalf("extra.rand.int = %v, want an int in [%d, %d]", v, tt.wantMin, tt.wantMax)
+				}
+			}
+		})
+	}
+}
+
+func TestEnvPlaceholders(t *testing.T) {
+	t.Setenv("EXTRA_TEST_ALLOWED", "visible")
+	t.Setenv("EXTRA_TEST_SECRET", "hidden")
+
+	e := newTestHandler(t, `extra_placeholders {
+		env_allow EXTRA_TEST_ALLOWED EXTRA_TEST_UNSET
+	}`)
+	repl := serveTestRequest(t, e)
+
+	if got, ok := repl.GetString("extra.env.EXTRA_TEST_ALLOWED"); !ok || got != "visible" {
+		t.Errorf("extra.env.EXTRA_TEST_ALLOWED = %q (set: %v), want %q", got, ok, "visible")
+	}
+	if got, ok := repl.GetString("extra.env.EXTRA_TEST_UNSET"); !ok || got != "" {
+		t.Errorf("extra.env.EXTRA_TEST_UNSET = %q (set: %v), want an empty string", got, ok)
+	}
+	if got, ok := repl.Get("extra.env.EXTRA_TEST_SECRET"); ok {
+		t.Errorf("extra.env.EXTRA_TEST_SECRET = %v, want no placeholder for a name not on the allowlist", got)
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"fmt"
+
+	"github.com/caddyserver/caddy/v2"
+)
+
+// humanizeBytes formats the given number of bytes with IEC binary prefixes and one decimal (e.g., "15.6 GiB").
+func humanizeBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
+
+// setBytes sets the placeholder with the given key to the number of bytes and,
+// if HumanizeBytes is enabled, the `<key>.human` placeholder to its human-readable form.
+func (e ExtraPlaceholders) setBytes(repl *caddy.Replacer, key string, b uint64) {
+	repl.Set(key, b)
+	if e.HumanizeBytes {
+		repl.Set(key+".human", humanizeBytes(b))
+	}
+}
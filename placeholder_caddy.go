@@ -15,12 +15,26 @@
 package extraplaceholders
 
 import (
+	"time"
+
 	"github.com/caddyserver/caddy/v2"
 )
 
-// setCaddyPlaceholders sets placeholders for the Caddy version.
+// setCaddyPlaceholders sets placeholders for the Caddy version, build information, instance ID, active requests,
+// uptime, provision count and last reload.
 func (e ExtraPlaceholders) setCaddyPlaceholders(repl *caddy.Replacer) {
-	simpleVersion, fullVersion := caddy.Version()
-	repl.Set("extra.caddy.version.simple", simpleVersion)
-	repl.Set("extra.caddy.version.full", fullVersion)
+	if !e.DisableCaddyVersionPlaceholders {
+		simpleVersion, fullVersion := caddy.Version()
+		repl.Set(e.key("caddy.version.simple"), simpleVersion)
+		repl.Set(e.key("caddy.version.full"), fullVersion)
+	}
+
+	repl.Set(e.key("caddy.build.go_version"), e.buildGoVersion)
+	repl.Set(e.key("caddy.build.main_path"), e.buildMainPath)
+	repl.Set(e.key("caddy.instance_id"), e.instanceID)
+
+	repl.Set(e.key("caddy.active_requests"), e.activeRequests.Load())
+	repl.Set(e.key("caddy.uptime"), time.Since(e.startTime).Truncate(time.Second).String())
+	repl.Set(e.key("caddy.provision_count"), provisionCount.Load())
+	repl.Set(e.key("caddy.last_reload"), e.startTime.Format(repl.ReplaceAll(e.timestampFormat, time.RFC3339)))
 }
@@ -0,0 +1,113 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+
+	"github.com/caddyserver/caddy/v2"
+	"github.com/shirou/gopsutil/v4/cpu"
+)
+
+// cpuSampler keeps the previous CPU times sample, so that the utilization can be computed
+// without blocking, relative to the previous call (or to boot time on the first call).
+type cpuSampler struct {
+	mu        sync.Mutex
+	lastTotal cpu.TimesStat
+	lastPer   []cpu.TimesStat
+}
+
+// cpuUtilization holds the aggregate and per-CPU utilization in percent.
+type cpuUtilization struct {
+	total  float64
+	perCPU []float64
+}
+
+// percent returns the aggregate and per-CPU utilization since the previous call.
+// The samples are taken while holding the lock, so that concurrent callers cannot store
+// an older sample after a newer one.
+func (s *cpuSampler) percent() (float64, []float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	total, err := cpu.Times(false)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(total) == 0 {
+		return 0, nil, fmt.Errorf("no CPU times available")
+	}
+	perCPU, err := cpu.Times(true)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	totalPercent := calculateCPUBusy(s.lastTotal, total[0])
+	perPercent := make([]float64, len(perCPU))
+	for i, t := range perCPU {
+		var last cpu.TimesStat
+		if i < len(s.lastPer) {
+			last = s.lastPer[i]
+		}
+		perPercent[i] = calculateCPUBusy(last, t)
+	}
+
+	s.lastTotal = total[0]
+	s.lastPer = perCPU
+
+	return totalPercent, perPercent, nil
+}
+
+// cpuAllBusy returns the total and the busy CPU time of the given sample.
+func cpuAllBusy(t cpu.TimesStat) (float64, float64) {
+	all := t.User + t.System + t.Idle + t.Nice + t.Iowait + t.Irq + t.Softirq + t.Steal
+	if runtime.GOOS != "linux" {
+		// On Linux, guest time is already accounted for in user and nice time.
+		all += t.Guest + t.GuestNice
+	}
+	return all, all - t.Idle - t.Iowait
+}
+
+// calculateCPUBusy returns the utilization in percent between two CPU times samples.
+func calculateCPUBusy(t1, t2 cpu.TimesStat) float64 {
+	t1All, t1Busy := cpuAllBusy(t1)
+	t2All, t2Busy := cpuAllBusy(t2)
+
+	if t2Busy <= t1Busy {
+		return 0
+	}
+	if t2All <= t1All {
+		return 100
+	}
+	return math.Min(100, math.Max(0, (t2Busy-t1Busy)/(t2All-t1All)*100))
+}
+
+// setCPUPlaceholders sets placeholders for the aggregate and per-CPU utilization and the logical CPU count.
+func (e ExtraPlaceholders) setCPUPlaceholders(repl *caddy.Replacer) {
+	repl.Set(e.key("cpu.count"), e.cpuCount)
+
+	totalPercent, perPercent, err := e.cpuPercent()
+	if err != nil {
+		repl.Set(e.key("cpu.percent"), "error retrieving cpu utilization")
+		return
+	}
+	repl.Set(e.key("cpu.percent"), totalPercent)
+	for i, p := range perPercent {
+		repl.Set(e.key(fmt.Sprintf("cpu.percent.%d", i)), p)
+	}
+}
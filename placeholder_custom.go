@@ -0,0 +1,76 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/caddyserver/caddy/v2"
+)
+
+// customOrder returns the keys of the custom placeholders in the order in which they have to be resolved,
+// so that a value referencing other `{extra.custom.*}` placeholders is resolved after them.
+// Keys without dependencies between them are ordered by name. A cyclic reference is an error.
+func (e ExtraPlaceholders) customOrder() ([]string, error) {
+	keys := make([]string, 0, len(e.Custom))
+	for key := range e.Custom {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	order := make([]string, 0, len(keys))
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(keys))
+	var visit func(key string) error
+	visit = func(key string) error {
+		switch state[key] {
+		case visiting:
+			return fmt.Errorf("invalid configuration: custom placeholder %q references itself in a cycle", key)
+		case done:
+			return nil
+		}
+		state[key] = visiting
+		for _, other := range keys {
+			if strings.Contains(e.Custom[key], "{"+e.key("custom."+other)+"}") {
+				if err := visit(other); err != nil {
+					return err
+				}
+			}
+		}
+		state[key] = done
+		order = append(order, key)
+		return nil
+	}
+	for _, key := range keys {
+		if err := visit(key); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// setCustomPlaceholders sets the placeholders defined with the `set` subdirective, with placeholders
+// within their values resolved. They are set in dependency order, so a value can reference other
+// custom placeholders.
+func (e ExtraPlaceholders) setCustomPlaceholders(repl *caddy.Replacer) {
+	for _, key := range e.customKeys {
+		repl.Set(e.key("custom."+key), repl.ReplaceAll(e.Custom[key], ""))
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCustomPlaceholders(t *testing.T) {
+	e := newTestHandler(t, `extra_placeholders {
+		set year "{extra.time.now.utc.year}"
+		set a "A"
+		set c "{extra.custom.b}C"
+		set b "{extra.custom.a}B"
+		set greeting "Hello {extra.custom.missing}"
+	}`)
+
+	// Repeat the requests, as the order of a map iteration would differ between them.
+	for i := 0; i < 20; i++ {
+		repl := serveTestRequest(t, e)
+
+		want := map[string]string{
+			"extra.custom.year":     strconv.Itoa(time.Now().UTC().Year()),
+			"extra.custom.a":        "A",
+			"extra.custom.b":        "AB",
+			"extra.custom.c":        "ABC",
+			"extra.custom.greeting": "Hello ",
+		}
+		for key, want := range want {
+			if got, _ := repl.GetString(key); got != want {
+				t.Fatalf("%s = %q, want %q", key, got, want)
+			}
+		}
+	}
+}
+
+func TestCustomPlaceholdersCycle(t *testing.T) {
+	for _, custom := range []map[string]string{
+		{"a": "{extra.custom.a}"},
+		{"a": "{extra.custom.b}", "b": "{extra.custom.c}", "c": "{extra.custom.a}"},
+	} {
+		e := &ExtraPlaceholders{Custom: custom}
+		if err := provisionTestHandler(t, e); err == nil {
+			t.Errorf("custom %v: want an error for a cyclic reference", custom)
+		}
+	}
+}
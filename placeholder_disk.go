@@ -0,0 +1,119 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/caddyserver/caddy/v2"
+	"github.com/shirou/gopsutil/v4/disk"
+	"go.uber.org/zap"
+)
+
+// setDiskPlaceholders sets placeholders for the disk and inode usage of the configured disk path
+// and of each aliased disk path.
+func (e ExtraPlaceholders) setDiskPlaceholders(repl *caddy.Replacer) {
+	e.setDiskUsagePlaceholders(repl, e.DiskPath, e.key("disk"))
+	for alias, path := range e.DiskPaths {
+		e.setDiskUsagePlaceholders(repl, path, e.key("disk."+alias))
+	}
+}
+
+// setDiskUsagePlaceholders sets placeholders for the disk and inode usage of the given disk path below base
+// (e.g., "extra.disk" or "extra.disk.logs"). The disk path may contain placeholders, which are resolved per request.
+// Readings are cached per resolved path, so aliases for the same path share them.
+func (e ExtraPlaceholders) setDiskUsagePlaceholders(repl *caddy.Replacer, diskPath, base string) {
+	usage, err := e.diskUsage(repl, diskPath)
+	if err != nil {
+		for _, name := range []string{"total", "free", "used", "used_percent", "inodes_total", "inodes_free", "inodes_used_percent"} {
+			repl.Set(base+"."+name, "error retrieving disk usage")
+		}
+		return
+	}
+	e.setBytes(repl, base+".total", usage.Total)
+	e.setBytes(repl, base+".free", usage.Free)
+	e.setBytes(repl, base+".used", usage.Used)
+	repl.Set(base+".used_percent", usage.UsedPercent)
+
+	// Some file systems (e.g., on Windows) don't have inodes, which results in zero values.
+	if usage.InodesTotal > 0 {
+		repl.Set(base+".inodes_total", usage.InodesTotal)
+		repl.Set(base+".inodes_free", usage.InodesFree)
+		repl.Set(base+".inodes_used_percent", usage.InodesUsedPercent)
+	} else {
+		for _, name := range []string{"inodes_total", "inodes_free", "inodes_used_percent"} {
+			repl.Set(base+"."+name, "")
+		}
+	}
+}
+
+// diskPathPrefix returns the cleaned static prefix of a disk path before its first placeholder, e.g. "/data/"
+// for "/data//{http.request.host}", and whether the path contains placeholders at all. A trailing separator
+// is kept, so that "/data/" does not match "/database".
+func diskPathPrefix(diskPath string) (string, bool) {
+	i := strings.Index(diskPath, "{")
+	if i < 0 {
+		return "", false
+	}
+	prefix := diskPath[:i]
+	if prefix == "" {
+		return "", true
+	}
+	cleaned := filepath.Clean(prefix)
+	if strings.HasSuffix(prefix, string(filepath.Separator)) && !strings.HasSuffix(cleaned, string(filepath.Separator)) {
+		cleaned += string(filepath.Separator)
+	}
+	return cleaned, true
+}
+
+// diskUsage returns the disk usage of the given disk path, resolved and cleaned. A path containing placeholders
+// must stay below its static prefix before the first placeholder (e.g., "/data/" for "/data/{http.request.host}"),
+// so that placeholders derived from client input cannot escape it, e.g. with "..".
+func (e ExtraPlaceholders) diskUsage(repl *caddy.Replacer, diskPath string) (*disk.UsageStat, error) {
+	path := filepath.Clean(repl.ReplaceAll(diskPath, ""))
+	if !filepath.IsAbs(path) {
+		return nil, fmt.Errorf("disk path %q is not absolute", path)
+	}
+
+	prefix, dynamic := diskPathPrefix(diskPath)
+	if dynamic && !strings.HasPrefix(path, prefix) && path+string(filepath.Separator) != prefix {
+		return nil, fmt.Errorf("disk path %q is not below %q", path, prefix)
+	}
+
+	return e.diskCache.get(path, func() (*disk.UsageStat, error) {
+		usage, err := disk.Usage(path)
+		if err != nil {
+			// A resolved path may be chosen by the client, so its failures are only logged at debug level
+			// to prevent flooding the logs.
+			if dynamic {
+				e.logger.Debug("Failed to retrieve disk usage", zap.String("path", path), zap.Error(err))
+			} else {
+				e.logger.Warn("Failed to retrieve disk usage", zap.String("path", path), zap.Error(err))
+			}
+		}
+		return usage, err
+	})
+}
+
+// validDiskPath reports whether the given disk path is absolute. A disk path containing placeholders can only
+// be fully checked once resolved per request, so its static prefix before the first placeholder must be absolute.
+func validDiskPath(diskPath string) bool {
+	if prefix, dynamic := diskPathPrefix(diskPath); dynamic {
+		return filepath.IsAbs(prefix)
+	}
+	return filepath.IsAbs(diskPath)
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"testing"
+
+	"github.com/caddyserver/caddy/v2"
+)
+
+// TestValidateDiskPath verifies that a disk path containing placeholders must start with an absolute static prefix.
+func TestValidateDiskPath(t *testing.T) {
+	for _, tt := range []struct {
+		path    string
+		wantErr bool
+	}{
+		{"/srv/data", false},
+		{"/data/{http.request.host}", false},
+		{"/data//{http.request.host}", false},
+		{"srv/data", true},
+		{"data/{http.request.host}", true},
+		{"{http.request.host}", true},
+	} {
+		t.Run(tt.path, func(t *testing.T) {
+			e := &ExtraPlaceholders{DiskPath: tt.path}
+			err := provisionTestHandler(t, e)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("disk_path %q: error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestDiskUsagePrefix verifies that a resolved disk path must stay below the cleaned static prefix.
+func TestDiskUsagePrefix(t *testing.T) {
+	e := newTestHandler(t, `extra_placeholders`)
+	for _, tt := range []struct {
+		diskPath string
+		tenant   string
+		wantErr  bool
+	}{
+		{"/{tenant}", "tmp", false},
+		{"//{tenant}", "tmp", false},
+		{"/tmp//{tenant}", ".", false},
+		{"/tmp/{tenant}", "../etc", true},
+		{"/tm{tenant}", "/../etc", true},
+	} {
+		repl := caddy.NewReplacer()
+		repl.Set("tenant", tt.tenant)
+		if _, err := e.diskUsage(repl, tt.diskPath); (err != nil) != tt.wantErr {
+			t.Errorf("diskUsage(%q) with tenant %q: error = %v, wantErr %v", tt.diskPath, tt.tenant, err, tt.wantErr)
+		}
+	}
+}
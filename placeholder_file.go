@@ -0,0 +1,97 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caddyserver/caddy/v2"
+	"go.uber.org/zap"
+)
+
+// maxReadFileSize is the maximum number of bytes read from a file for the `{extra.file.<alias>}` placeholders.
+const maxReadFileSize = 64 << 10
+
+// fileCache caches the trimmed contents of a file. After the TTL has expired, the file is only
+// read again if its modification time has changed. It is safe for concurrent use.
+type fileCache struct {
+	mu      sync.Mutex
+	path    string
+	ttl     time.Duration
+	logger  *zap.Logger
+	checked time.Time
+	modTime time.Time
+	content string
+}
+
+// newFileCache returns a fileCache for the file at the given path with the given TTL.
+// Read errors are logged to the given logger.
+func newFileCache(path string, ttl time.Duration, logger *zap.Logger) *fileCache {
+	return &fileCache{path: path, ttl: ttl, logger: logger}
+}
+
+// get returns the cached contents of the file, or reads them again if the TTL has expired
+// and the file has been modified since. If the file cannot be read, an empty string is returned.
+func (c *fileCache) get() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.checked.IsZero() && time.Since(c.checked) < c.ttl {
+		return c.content
+	}
+	c.checked = time.Now()
+
+	content, modTime, err := c.read()
+	if err != nil {
+		c.logger.Warn("Failed to read file", zap.String("path", c.path), zap.Error(err))
+	}
+	c.content, c.modTime = content, modTime
+	return c.content
+}
+
+// read returns the trimmed contents of the file and its modification time, reusing the cached
+// contents if the modification time is unchanged. At most maxReadFileSize bytes are read.
+func (c *fileCache) read() (string, time.Time, error) {
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if !c.modTime.IsZero() && info.ModTime().Equal(c.modTime) {
+		return c.content, c.modTime, nil
+	}
+
+	f, err := os.Open(c.path)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer f.Close()
+
+	b, err := io.ReadAll(io.LimitReader(f, maxReadFileSize))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return strings.TrimSpace(string(b)), info.ModTime(), nil
+}
+
+// setFilePlaceholders sets placeholders for the contents of the configured files.
+func (e ExtraPlaceholders) setFilePlaceholders(repl *caddy.Replacer) {
+	for alias, cache := range e.fileCaches {
+		repl.Set(e.key("file."+alias), cache.get())
+	}
+}
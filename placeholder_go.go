@@ -0,0 +1,50 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"math"
+	"runtime"
+	"time"
+
+	"github.com/caddyserver/caddy/v2"
+)
+
+// readMemStats returns the current Go runtime memory statistics.
+func readMemStats() (*runtime.MemStats, error) {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return &m, nil
+}
+
+// setGoPlaceholders sets placeholders for the Go runtime and its garbage collector.
+// The memory statistics are reused for memStatsCacheTTL.
+func (e ExtraPlaceholders) setGoPlaceholders(repl *caddy.Replacer) {
+	repl.Set(e.key("go.runtime.numcpu"), runtime.NumCPU())
+	repl.Set(e.key("go.runtime.gomaxprocs"), runtime.GOMAXPROCS(0))
+	repl.Set(e.key("go.runtime.numcgocall"), runtime.NumCgoCall())
+	if e.GoroutineSoftLimit > 0 {
+		repl.Set(e.key("go.runtime.goroutine_percent"), int(math.Round(float64(runtime.NumGoroutine())/float64(e.GoroutineSoftLimit)*100)))
+	}
+
+	memStats, _ := e.memStatsCache.get(readMemStats)
+	var pauseLast time.Duration
+	if memStats.NumGC > 0 {
+		// PauseNs is a circular buffer, with the most recent pause at index (NumGC+255)%256.
+		pauseLast = time.Duration(memStats.PauseNs[(memStats.NumGC+255)%256])
+	}
+	repl.Set(e.key("go.gc.pause_last"), pauseLast.String())
+	repl.Set(e.key("go.gc.pause_total"), time.Duration(memStats.PauseTotalNs).String())
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"hash/fnv"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/caddyserver/caddy/v2"
+	"github.com/caddyserver/caddy/v2/modules/caddyhttp"
+)
+
+// setHashPlaceholders sets placeholders for a stable hash of the client IP and, if HashBuckets is configured,
+// the bucket derived from it.
+func (e ExtraPlaceholders) setHashPlaceholders(repl *caddy.Replacer, r *http.Request) {
+	// Prefer the client IP determined by Caddy, which respects trusted proxies, over the remote address.
+	clientIP, ok := caddyhttp.GetVar(r.Context(), caddyhttp.ClientIPVarKey).(string)
+	if !ok || clientIP == "" {
+		clientIP = r.RemoteAddr
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			clientIP = host
+		}
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(clientIP))
+	sum := h.Sum64()
+	repl.Set(e.key("hash.client_ip"), strconv.FormatUint(sum, 16))
+
+	if e.HashBuckets > 0 {
+		repl.Set(e.key("hash.client_ip.bucket"), sum%uint64(e.HashBuckets))
+	}
+}
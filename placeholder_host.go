@@ -0,0 +1,168 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/caddyserver/caddy/v2"
+	"github.com/shirou/gopsutil/v4/load"
+	"go.uber.org/zap"
+)
+
+// kernelStats holds the kernel activity counters and the available entropy, together with the errors of their retrieval.
+type kernelStats struct {
+	contextSwitches    int
+	contextSwitchesErr error
+	interrupts         uint64
+	interruptsErr      error
+	entropyAvail       int
+	entropyAvailErr    error
+}
+
+// kernelStatsSampler caches the kernel activity counters and ensures that unsupported counters are only logged once.
+type kernelStatsSampler struct {
+	cache                  *ttlCache[kernelStats]
+	contextSwitchesErrOnce sync.Once
+	interruptsErrOnce      sync.Once
+	entropyAvailErrOnce    sync.Once
+}
+
+// readKernelStats retrieves the number of context switches and interrupts since boot and the available entropy.
+func readKernelStats() (kernelStats, error) {
+	var stats kernelStats
+	if misc, err := load.Misc(); err == nil {
+		stats.contextSwitches = misc.Ctxt
+	} else {
+		stats.contextSwitchesErr = err
+	}
+	stats.interrupts, stats.interruptsErr = procStatInterrupts()
+	stats.entropyAvail, stats.entropyAvailErr = entropyAvail()
+	return stats, nil
+}
+
+// entropyAvail returns the available entropy of the kernel's random number generator in bits.
+// It is only available on Linux.
+func entropyAvail() (int, error) {
+	b, err := os.ReadFile("/proc/sys/kernel/random/entropy_avail")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(b)))
+}
+
+// procStatInterrupts returns the total number of interrupts since boot from the "intr" line of /proc/stat.
+// It is only available on Linux.
+func procStatInterrupts() (uint64, error) {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	// The "intr" line lists the count of each interrupt and can be very long.
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "intr" {
+			return strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("no interrupts found in /proc/stat")
+}
+
+// cgroupCPUQuota returns the effective CPU limit of the cgroup of the Caddy process in number of CPUs
+// (e.g., "1.5"), or "max" if unlimited, from the cpu.max file of cgroup v2. It is only available on Linux.
+func cgroupCPUQuota() (string, error) {
+	path := "/sys/fs/cgroup/cpu.max"
+	// The cgroup v2 of the process is listed with hierarchy ID 0, e.g. "0::/system.slice/caddy.service".
+	if b, err := os.ReadFile("/proc/self/cgroup"); err == nil {
+		for _, line := range strings.Split(string(b), "\n") {
+			if rel, ok := strings.CutPrefix(line, "0::"); ok {
+				path = filepath.Join("/sys/fs/cgroup", rel, "cpu.max")
+			}
+		}
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	// The file contains the quota and the period in microseconds, e.g. "150000 100000" or "max 100000".
+	fields := strings.Fields(string(b))
+	if len(fields) != 2 {
+		return "", fmt.Errorf("unexpected content of %s: %q", path, b)
+	}
+	if fields[0] == "max" {
+		return "max", nil
+	}
+	quota, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return "", err
+	}
+	period, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || period <= 0 {
+		return "", fmt.Errorf("invalid period in %s: %q", path, fields[1])
+	}
+	return strconv.FormatFloat(quota/period, 'f', -1, 64), nil
+}
+
+// setHostPlaceholders sets placeholders for the kernel activity counters, the available entropy, the cgroup CPU limit
+// and the virtualization.
+// Values that are not supported on this platform are set to an empty value.
+func (e ExtraPlaceholders) setHostPlaceholders(repl *caddy.Replacer) {
+	repl.Set(e.key("host.cgroup_cpu_quota"), e.cgroupCPUQuota)
+	repl.Set(e.key("host.virtualization"), e.virtualizationSystem)
+	repl.Set(e.key("host.virtualization_role"), e.virtualizationRole)
+
+	stats, _ := e.kernelStatsSampler.cache.get(readKernelStats)
+
+	if stats.contextSwitchesErr == nil {
+		repl.Set(e.key("host.context_switches"), stats.contextSwitches)
+	} else {
+		e.kernelStatsSampler.contextSwitchesErrOnce.Do(func() {
+			e.logger.Warn("Failed to retrieve the number of context switches", zap.Error(stats.contextSwitchesErr))
+		})
+		repl.Set(e.key("host.context_switches"), "")
+	}
+
+	if stats.interruptsErr == nil {
+		repl.Set(e.key("host.interrupts"), stats.interrupts)
+	} else {
+		e.kernelStatsSampler.interruptsErrOnce.Do(func() {
+			e.logger.Warn("Failed to retrieve the number of interrupts", zap.Error(stats.interruptsErr))
+		})
+		repl.Set(e.key("host.interrupts"), "")
+	}
+
+	if stats.entropyAvailErr == nil {
+		repl.Set(e.key("host.entropy_avail"), stats.entropyAvail)
+	} else {
+		e.kernelStatsSampler.entropyAvailErrOnce.Do(func() {
+			e.logger.Warn("Failed to retrieve the available entropy", zap.Error(stats.entropyAvailErr))
+		})
+		repl.Set(e.key("host.entropy_avail"), "")
+	}
+}
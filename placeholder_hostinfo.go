@@ -15,19 +15,71 @@
 package extraplaceholders
 
 import (
+	"net"
 	"time"
 
 	"github.com/caddyserver/caddy/v2"
 	"github.com/shirou/gopsutil/v4/host"
+	"go.uber.org/zap"
 )
 
-// setHostinfoPlaceholders sets placeholders for system uptime in a human-readable format.
+// setHostinfoPlaceholders sets placeholders for system uptime in a human-readable format, the boot time
+// the static host information and the number of logged-in users. The uptime is derived from the boot time
+// determined during provisioning, and the host information is cached as well, so no syscall is needed.
+// The number of logged-in users is reused for usersCacheTTL.
 func (e ExtraPlaceholders) setHostinfoPlaceholders(repl *caddy.Replacer) {
-	uptime, err := host.Uptime()
+	if !e.bootTime.IsZero() {
+		uptimeDuration := time.Since(e.bootTime).Truncate(time.Second)
+		repl.Set(e.key("hostinfo.uptime"), uptimeDuration.String())
+		repl.Set(e.key("hostinfo.boottime"), e.bootTime.Format(repl.ReplaceAll(e.timestampFormat, time.RFC3339)))
+	} else {
+		repl.Set(e.key("hostinfo.uptime"), "error retrieving uptime")
+		repl.Set(e.key("hostinfo.boottime"), "error retrieving boot time")
+	}
+
+	if e.hostInfo != nil {
+		repl.Set(e.key("hostinfo.hostname"), e.hostInfo.Hostname)
+		repl.Set(e.key("hostinfo.os"), e.hostInfo.OS)
+		repl.Set(e.key("hostinfo.platform"), e.hostInfo.Platform)
+		repl.Set(e.key("hostinfo.kernel_version"), e.hostInfo.KernelVersion)
+	} else {
+		for _, name := range []string{"hostname", "os", "platform", "kernel_version"} {
+			repl.Set(e.key("hostinfo."+name), "error retrieving host info")
+		}
+	}
+
+	repl.Set(e.key("hostinfo.local_ip"), e.localIP)
+
+	users, err := e.usersCache.get(func() (int, error) {
+		users, err := host.Users()
+		if err != nil {
+			e.usersErrOnce.Do(func() {
+				e.logger.Warn("Failed to retrieve logged-in users", zap.Error(err))
+			})
+		}
+		return len(users), err
+	})
 	if err == nil {
-		uptimeDuration := time.Duration(uptime) * time.Second
-		repl.Set("extra.hostinfo.uptime", uptimeDuration.String())
+		repl.Set(e.key("hostinfo.users"), users)
 	} else {
-		repl.Set("extra.hostinfo.uptime", "error retrieving uptime")
+		repl.Set(e.key("hostinfo.users"), "error retrieving users")
+	}
+}
+
+// outboundIP returns the local IP address used for outbound connections. It "connects" a UDP socket
+// to a public address, which only selects the route and local address, without sending any packets.
+// IPv4 is preferred, IPv6 is used as fallback.
+func outboundIP() (string, error) {
+	var lastErr error
+	for _, addr := range []string{"8.8.8.8:80", "[2001:4860:4860::8888]:80"} {
+		conn, err := net.Dial("udp", addr)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		localAddr := conn.LocalAddr().(*net.UDPAddr)
+		conn.Close()
+		return localAddr.IP.String(), nil
 	}
+	return "", lastErr
 }
@@ -15,16 +15,54 @@
 package extraplaceholders
 
 import (
+	"math"
+	"runtime"
+	"strconv"
+
 	"github.com/caddyserver/caddy/v2"
-	"github.com/shirou/gopsutil/v4/load"
 )
 
-// setLoadavgPlaceholders sets placeholders for system load averages (1, 5, and 15 minutes).
+// setLoadavgPlaceholders sets placeholders for system load averages (1, 5, and 15 minutes),
+// both as raw values and normalized by the number of logical CPUs.
 func (e ExtraPlaceholders) setLoadavgPlaceholders(repl *caddy.Replacer) {
-	loadAvg, err := load.Avg()
+	loadAvg, err := e.loadAvg()
 	if err == nil {
-		repl.Set("extra.loadavg.1", loadAvg.Load1)
-		repl.Set("extra.loadavg.5", loadAvg.Load5)
-		repl.Set("extra.loadavg.15", loadAvg.Load15)
+		repl.Set(e.key("loadavg.1"), e.formatLoadavg(loadAvg.Load1))
+		repl.Set(e.key("loadavg.5"), e.formatLoadavg(loadAvg.Load5))
+		repl.Set(e.key("loadavg.15"), e.formatLoadavg(loadAvg.Load15))
+		prec := -1
+		if e.LoadavgPrecision > 0 {
+			prec = e.LoadavgPrecision
+		}
+		repl.Set(e.key("loadavg.all"), strconv.FormatFloat(loadAvg.Load1, 'f', prec, 64)+","+
+			strconv.FormatFloat(loadAvg.Load5, 'f', prec, 64)+","+
+			strconv.FormatFloat(loadAvg.Load15, 'f', prec, 64))
+
+		numCPU := float64(runtime.NumCPU())
+		repl.Set(e.key("loadavg.1.normalized"), e.formatLoadavg(loadAvg.Load1/numCPU))
+		repl.Set(e.key("loadavg.5.normalized"), e.formatLoadavg(loadAvg.Load5/numCPU))
+		repl.Set(e.key("loadavg.15.normalized"), e.formatLoadavg(loadAvg.Load15/numCPU))
+
+		// Scaled integer values for consumers that can't parse floats. The load averages have two decimals,
+		// so rounding avoids floating point errors like 1.23*100 = 122.99999999999999.
+		repl.Set(e.key("loadavg.1.centi"), int(math.Round(loadAvg.Load1*100)))
+		repl.Set(e.key("loadavg.5.centi"), int(math.Round(loadAvg.Load5*100)))
+		repl.Set(e.key("loadavg.15.centi"), int(math.Round(loadAvg.Load15*100)))
+
+		if e.LoadavgMax > 0 {
+			repl.Set(e.key("loadavg.1.percent"), math.Min(100, math.Max(0, loadAvg.Load1/e.LoadavgMax*100)))
+		}
+		if e.LoadavgThreshold > 0 {
+			repl.Set(e.key("loadavg.1.over_threshold"), loadAvg.Load1 > e.LoadavgThreshold)
+		}
+	}
+}
+
+// formatLoadavg formats a load average with the configured LoadavgPrecision. If not configured,
+// the float is returned as is, which keeps its full precision and its type, e.g. in CEL expressions.
+func (e ExtraPlaceholders) formatLoadavg(v float64) any {
+	if e.LoadavgPrecision > 0 {
+		return strconv.FormatFloat(v, 'f', e.LoadavgPrecision, 64)
 	}
+	return v
 }
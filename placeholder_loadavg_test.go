@@ -0,0 +1,44 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"testing"
+
+	"github.com/caddyserver/caddy/v2"
+)
+
+// BenchmarkSetLoadavgPlaceholders compares load average readings reused from the TTL cache
+// with a reading per request, which is forced with a TTL of 1ns.
+func BenchmarkSetLoadavgPlaceholders(b *testing.B) {
+	for _, bm := range []struct {
+		name   string
+		config string
+	}{
+		{"cached", `extra_placeholders`},
+		{"uncached", `extra_placeholders {
+			loadavg_cache_ttl 1ns
+		}`},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			e := newTestHandler(b, bm.config)
+			repl := caddy.NewReplacer()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				e.setLoadavgPlaceholders(repl)
+			}
+		})
+	}
+}
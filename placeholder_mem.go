@@ -0,0 +1,33 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"github.com/caddyserver/caddy/v2"
+)
+
+// setMemPlaceholders sets placeholders for the swap memory usage.
+func (e ExtraPlaceholders) setMemPlaceholders(repl *caddy.Replacer) {
+	swap, err := e.swapMemory()
+	if err != nil {
+		for _, name := range []string{"swap_total", "swap_used", "swap_used_percent"} {
+			repl.Set(e.key("mem."+name), "error retrieving swap usage")
+		}
+		return
+	}
+	e.setBytes(repl, e.key("mem.swap_total"), swap.Total)
+	e.setBytes(repl, e.key("mem.swap_used"), swap.Used)
+	repl.Set(e.key("mem.swap_used_percent"), swap.UsedPercent)
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"fmt"
+
+	"github.com/caddyserver/caddy/v2"
+	psnet "github.com/shirou/gopsutil/v4/net"
+	"go.uber.org/zap"
+)
+
+// netIOCounters returns the network I/O counters of the given interface,
+// or aggregated across all interfaces if iface is empty.
+func netIOCounters(iface string) (psnet.IOCountersStat, error) {
+	counters, err := psnet.IOCounters(iface != "")
+	if err != nil {
+		return psnet.IOCountersStat{}, err
+	}
+	if iface == "" {
+		if len(counters) == 0 {
+			return psnet.IOCountersStat{}, fmt.Errorf("no network I/O counters available")
+		}
+		return counters[0], nil
+	}
+	for _, c := range counters {
+		if c.Name == iface {
+			return c, nil
+		}
+	}
+	return psnet.IOCountersStat{}, fmt.Errorf("network interface %q not found", iface)
+}
+
+// setNetPlaceholders sets placeholders for the network I/O counters of the configured interface,
+// or aggregated across all interfaces if none is configured.
+func (e ExtraPlaceholders) setNetPlaceholders(repl *caddy.Replacer) {
+	counters, err := e.netCache.get(func() (psnet.IOCountersStat, error) {
+		counters, err := netIOCounters(e.NetInterface)
+		if err != nil {
+			e.logger.Warn("Failed to retrieve network I/O counters", zap.String("interface", e.NetInterface), zap.Error(err))
+		}
+		return counters, err
+	})
+	if err != nil {
+		repl.Set(e.key("net.bytes_sent"), "error retrieving network counters")
+		repl.Set(e.key("net.bytes_recv"), "error retrieving network counters")
+		return
+	}
+	e.setBytes(repl, e.key("net.bytes_sent"), counters.BytesSent)
+	e.setBytes(repl, e.key("net.bytes_recv"), counters.BytesRecv)
+}
@@ -0,0 +1,154 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"sync"
+	"time"
+
+	"github.com/caddyserver/caddy/v2"
+	"github.com/shirou/gopsutil/v4/process"
+	"go.uber.org/zap"
+)
+
+// processSampler wraps the handle of the Caddy process and keeps the previous CPU times sample,
+// so that the CPU utilization can be computed without blocking, relative to the previous call.
+type processSampler struct {
+	proc *process.Process
+
+	mu       sync.Mutex
+	lastCPU  float64
+	lastTime time.Time
+
+	// numFDsErrOnce ensures that the failure to count the open file descriptors, which
+	// is expected on platforms other than Linux, is logged only once.
+	numFDsErrOnce sync.Once
+}
+
+// cpuPercent returns the CPU utilization of the process since the previous call (or since the process
+// start on the first call). As with top, 100% corresponds to one fully used CPU core.
+// The sample is taken while holding the lock, so that concurrent callers cannot store
+// an older sample after a newer one.
+func (s *processSampler) cpuPercent() (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	times, err := s.proc.Times()
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	cpuTime := times.User + times.System
+
+	lastCPU, lastTime := s.lastCPU, s.lastTime
+	if lastTime.IsZero() {
+		createTime, err := s.proc.CreateTime()
+		if err != nil {
+			return 0, err
+		}
+		lastTime = time.UnixMilli(createTime)
+	}
+	s.lastCPU, s.lastTime = cpuTime, now
+
+	wall := now.Sub(lastTime).Seconds()
+	if wall <= 0 {
+		return 0, nil
+	}
+	return (cpuTime - lastCPU) / wall * 100, nil
+}
+
+// processStats holds a reading of the resource usage of the Caddy process, together with the errors of its retrieval.
+type processStats struct {
+	cpuPercent    float64
+	cpuErr        error
+	memRSS        uint64
+	memErr        error
+	numThreads    int32
+	numThreadsErr error
+	numFDs        int32
+	numFDsErr     error
+}
+
+// stats reads the resource usage of the Caddy process. The errors are kept per value,
+// so that a failure to read one of them does not hide the others.
+func (s *processSampler) stats() processStats {
+	var st processStats
+	st.cpuPercent, st.cpuErr = s.cpuPercent()
+	if memInfo, err := s.proc.MemoryInfo(); err == nil {
+		st.memRSS = memInfo.RSS
+	} else {
+		st.memErr = err
+	}
+	st.numThreads, st.numThreadsErr = s.proc.NumThreads()
+	st.numFDs, st.numFDsErr = s.proc.NumFDs()
+	return st
+}
+
+// setProcessPlaceholders sets placeholders for the resource usage of the Caddy process
+// and the number of processes on the system.
+func (e ExtraPlaceholders) setProcessPlaceholders(repl *caddy.Replacer) {
+	processCount, err := e.processCountCache.get(func() (int, error) {
+		pids, err := process.Pids()
+		if err != nil {
+			e.logger.Warn("Failed to enumerate the processes", zap.Error(err))
+		}
+		return len(pids), err
+	})
+	if err == nil {
+		repl.Set(e.key("host.process_count"), processCount)
+	} else {
+		repl.Set(e.key("host.process_count"), "error retrieving process info")
+	}
+
+	repl.Set(e.key("process.start_time"), e.startTime.Format(repl.ReplaceAll(e.timestampFormat, time.RFC3339)))
+
+	if e.processSampler == nil {
+		for _, name := range []string{"cpu_percent", "mem_rss", "num_threads", "num_fds"} {
+			repl.Set(e.key("process."+name), "error retrieving process info")
+		}
+		return
+	}
+	// The reading never fails as a whole, the errors are kept per value.
+	stats, _ := e.processStatsCache.get(func() (processStats, error) {
+		return e.processSampler.stats(), nil
+	})
+
+	if stats.cpuErr == nil {
+		repl.Set(e.key("process.cpu_percent"), stats.cpuPercent)
+	} else {
+		repl.Set(e.key("process.cpu_percent"), "error retrieving process info")
+	}
+
+	if stats.memErr == nil {
+		e.setBytes(repl, e.key("process.mem_rss"), stats.memRSS)
+	} else {
+		repl.Set(e.key("process.mem_rss"), "error retrieving process info")
+	}
+
+	if stats.numThreadsErr == nil {
+		repl.Set(e.key("process.num_threads"), stats.numThreads)
+	} else {
+		repl.Set(e.key("process.num_threads"), "error retrieving process info")
+	}
+
+	if stats.numFDsErr == nil {
+		repl.Set(e.key("process.num_fds"), stats.numFDs)
+	} else {
+		e.processSampler.numFDsErrOnce.Do(func() {
+			e.logger.Warn("Failed to count the open file descriptors of the Caddy process", zap.Error(stats.numFDsErr))
+		})
+		repl.Set(e.key("process.num_fds"), "")
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import "testing"
+
+// TestProcessPlaceholdersCached verifies that requests arriving close together reuse the same
+// process reading, instead of dividing clock-tick noise by a tiny interval.
+func TestProcessPlaceholdersCached(t *testing.T) {
+	e := newTestHandler(t, `extra_placeholders {
+		placeholders process
+	}`)
+	if e.processSampler == nil {
+		t.Skip("process handle not available")
+	}
+
+	first := serveTestRequest(t, e)
+	second := serveTestRequest(t, e)
+	for _, key := range []string{"extra.process.cpu_percent", "extra.process.mem_rss", "extra.process.num_threads"} {
+		v1, _ := first.Get(key)
+		v2, _ := second.Get(key)
+		if v1 != v2 {
+			t.Errorf("%s = %v, then %v, want the cached reading", key, v1, v2)
+		}
+	}
+}
@@ -15,17 +15,245 @@
 package extraplaceholders
 
 import (
+	crand "crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
 	"math/rand"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/caddyserver/caddy/v2"
+	"go.uber.org/zap"
 )
 
-// setRandPlaceholders sets placeholders for random float and integer values.
+// lockedSource is a rand.Source64 that is safe for concurrent use by multiple goroutines.
+// Unlike the package-level functions of math/rand, a *rand.Rand created with rand.NewSource is not.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source64
+}
+
+// newLockedSource returns a lockedSource seeded with the given value.
+func newLockedSource(seed int64) *lockedSource {
+	return &lockedSource{src: rand.NewSource(seed).(rand.Source64)}
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
+// setRandPlaceholders sets placeholders for random float, integer, string, UUID, hex, weighted choice, duration,
+// list item and boolean values.
 func (e ExtraPlaceholders) setRandPlaceholders(repl *caddy.Replacer) {
-	repl.Set("extra.rand.float", rand.Float64())
-	if e.RandIntMax > e.RandIntMin {
-		repl.Set("extra.rand.int", rand.Intn(e.RandIntMax-e.RandIntMin+1)+e.RandIntMin)
+	if f, err := e.randFloat64(); err == nil {
+		if e.RandFloatPrecision > 0 {
+			repl.Set(e.key("rand.float"), strconv.FormatFloat(f, 'f', e.RandFloatPrecision, 64))
+		} else {
+			repl.Set(e.key("rand.float"), f)
+		}
+	} else {
+		e.setRandError(repl, e.key("rand.float"), err)
+	}
+
+	if n, err := e.randNormFloat64(); err == nil {
+		v := e.RandNormalMean + n*e.RandNormalStddev
+		if e.RandFloatPrecision > 0 {
+			repl.Set(e.key("rand.normal"), strconv.FormatFloat(v, 'f', e.RandFloatPrecision, 64))
+		} else {
+			repl.Set(e.key("rand.normal"), v)
+		}
+	} else {
+		e.setRandError(repl, e.key("rand.normal"), err)
+	}
+
+	// Default range 0-100 if not properly configured
+	min, n := 0, 101
+	if e.randIntMax >= e.randIntMin {
+		min, n = e.randIntMin, e.randIntMax-e.randIntMin+1
+	}
+	if i, err := e.randIntn(n); err == nil {
+		repl.Set(e.key("rand.int"), i+min)
+		repl.Set(e.key("rand.int_padded"), fmt.Sprintf("%0*d", e.randIntPadWidth, i+min))
+	} else {
+		e.setRandError(repl, e.key("rand.int"), err)
+		e.setRandError(repl, e.key("rand.int_padded"), err)
+	}
+
+	if s, err := randString(e.randIntn, e.RandStringLength, e.RandStringAlphabet); err == nil {
+		repl.Set(e.key("rand.string"), s)
+	} else {
+		e.setRandError(repl, e.key("rand.string"), err)
+	}
+
+	uuid, err := newUUIDv4()
+	if err != nil {
+		e.logger.Error("Failed to generate UUID", zap.Error(err))
+		repl.Set(e.key("rand.uuid"), "error generating uuid")
+	} else {
+		repl.Set(e.key("rand.uuid"), uuid)
+	}
+
+	token := make([]byte, e.RandHexBytes)
+	if _, err := crand.Read(token); err == nil {
+		repl.Set(e.key("rand.hex"), hex.EncodeToString(token))
+	} else {
+		e.setRandError(repl, e.key("rand.hex"), err)
+	}
+
+	if e.randChoiceTotal > 0 {
+		if r, err := e.randIntn(e.randChoiceTotal); err == nil {
+			repl.Set(e.key("rand.choice"), pickRandChoice(e.RandChoices, r))
+		} else {
+			e.setRandError(repl, e.key("rand.choice"), err)
+		}
+	}
+
+	if e.RandDurationMax > e.RandDurationMin {
+		// Durations are int64 nanoseconds, which may overflow an int on 32-bit platforms.
+		if i, err := e.randInt63n(int64(e.RandDurationMax - e.RandDurationMin + 1)); err == nil {
+			d := time.Duration(e.RandDurationMin) + time.Duration(i)
+			repl.Set(e.key("rand.duration"), d.String())
+			repl.Set(e.key("rand.duration.seconds"), int64(d/time.Second))
+		} else {
+			e.setRandError(repl, e.key("rand.duration"), err)
+			e.setRandError(repl, e.key("rand.duration.seconds"), err)
+		}
+	}
+
+	if len(e.RandList) > 0 {
+		if i, err := e.randIntn(len(e.RandList)); err == nil {
+			repl.Set(e.key("rand.item"), e.RandList[i])
+		} else {
+			e.setRandError(repl, e.key("rand.item"), err)
+		}
+	}
+
+	if f, err := e.randFloat64(); err == nil {
+		repl.Set(e.key("rand.bool"), f < e.randBoolProbability)
 	} else {
-		repl.Set("extra.rand.int", rand.Intn(101)) // Default range 0-100 if not properly configured
+		e.setRandError(repl, e.key("rand.bool"), err)
+	}
+}
+
+// pickRandChoice returns the value of the choice whose cumulative weight range contains r,
+// where r must be in [0, total weight).
+func pickRandChoice(choices []RandChoice, r int) string {
+	for _, choice := range choices {
+		if r < choice.Weight {
+			return choice.Value
+		}
+		r -= choice.Weight
+	}
+	return ""
+}
+
+// setRandError logs the failure to generate a random value and sets the placeholder to an error value.
+func (e ExtraPlaceholders) setRandError(repl *caddy.Replacer, key string, err error) {
+	e.logger.Error("Failed to generate random value", zap.String("placeholder", key), zap.Error(err))
+	repl.Set(key, "error generating random value")
+}
+
+// randIntn returns a random integer in [0, n), generated from crypto/rand if RandCrypto is enabled,
+// or from the per-instance math/rand source otherwise.
+func (e ExtraPlaceholders) randIntn(n int) (int, error) {
+	if !e.RandCrypto {
+		return e.rng.Intn(n), nil
+	}
+	v, err := crand.Int(crand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(v.Int64()), nil
+}
+
+// randInt63n returns a random int64 in [0, n), generated from crypto/rand if RandCrypto is enabled,
+// or from the per-instance math/rand source otherwise.
+func (e ExtraPlaceholders) randInt63n(n int64) (int64, error) {
+	if !e.RandCrypto {
+		return e.rng.Int63n(n), nil
+	}
+	v, err := crand.Int(crand.Reader, big.NewInt(n))
+	if err != nil {
+		return 0, err
+	}
+	return v.Int64(), nil
+}
+
+// randFloat64 returns a random float in [0.0, 1.0), generated from crypto/rand if RandCrypto is enabled,
+// or from the per-instance math/rand source otherwise.
+func (e ExtraPlaceholders) randFloat64() (float64, error) {
+	if !e.RandCrypto {
+		return e.rng.Float64(), nil
+	}
+	// Use 53 random bits, the precision of a float64 mantissa.
+	v, err := crand.Int(crand.Reader, big.NewInt(1<<53))
+	if err != nil {
+		return 0, err
+	}
+	return float64(v.Int64()) / (1 << 53), nil
+}
+
+// randNormFloat64 returns a random float from the standard normal distribution (mean 0, standard deviation 1),
+// generated from crypto/rand if RandCrypto is enabled, or from the per-instance math/rand source otherwise.
+func (e ExtraPlaceholders) randNormFloat64() (float64, error) {
+	if !e.RandCrypto {
+		return e.rng.NormFloat64(), nil
+	}
+	// Box-Muller transform of two uniformly distributed values, using 1-u1 in (0, 1] to avoid log(0).
+	u1, err := e.randFloat64()
+	if err != nil {
+		return 0, err
+	}
+	u2, err := e.randFloat64()
+	if err != nil {
+		return 0, err
+	}
+	return math.Sqrt(-2*math.Log(1-u1)) * math.Cos(2*math.Pi*u2), nil
+}
+
+// newUUIDv4 returns a RFC 4122 version 4 UUID in its canonical 8-4-4-4-12 form,
+// generated from the cryptographically secure random source.
+func newUUIDv4() (string, error) {
+	var u [16]byte
+	if _, err := crand.Read(u[:]); err != nil {
+		return "", err
+	}
+	u[6] = (u[6] & 0x0f) | 0x40 // Version 4
+	u[8] = (u[8] & 0x3f) | 0x80 // Variant RFC 4122
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16]), nil
+}
+
+// randString returns a random string of the given length, using characters from the given alphabet
+// picked by the given intn function.
+func randString(intn func(int) (int, error), length int, alphabet string) (string, error) {
+	if length <= 0 || alphabet == "" {
+		return "", nil
+	}
+	chars := []rune(alphabet)
+	b := make([]rune, length)
+	for i := range b {
+		idx, err := intn(len(chars))
+		if err != nil {
+			return "", err
+		}
+		b[i] = chars[idx]
 	}
+	return string(b), nil
 }
@@ -0,0 +1,75 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/caddyserver/caddy/v2"
+	"github.com/caddyserver/caddy/v2/modules/caddyhttp"
+)
+
+// setRequestPlaceholders sets placeholders for details of the current request.
+// handlerStart is the time the request entered this handler.
+func (e ExtraPlaceholders) setRequestPlaceholders(repl *caddy.Replacer, r *http.Request, handlerStart time.Time) {
+	// Set the time elapsed since Caddy started handling the request, as recorded in the
+	// "start_time" request variable. If unavailable, it is measured from the handler entry.
+	start, ok := caddyhttp.GetVar(r.Context(), "start_time").(time.Time)
+	if !ok {
+		start = handlerStart
+	}
+	repl.Set(e.key("request.elapsed"), time.Since(start).String())
+
+	// Set TLS connection details, empty for plaintext requests
+	tlsVersion, tlsCipherSuite := "", ""
+	if r.TLS != nil {
+		tlsVersion = strings.TrimPrefix(tls.VersionName(r.TLS.Version), "TLS ")
+		tlsCipherSuite = tls.CipherSuiteName(r.TLS.CipherSuite)
+	}
+	repl.Set(e.key("request.tls.version"), tlsVersion)
+	repl.Set(e.key("request.tls.cipher_suite"), tlsCipherSuite)
+
+	// Set the absolute base URL. The host already includes the port, if the client sent one.
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	repl.Set(e.key("request.base_url"), scheme+"://"+r.Host)
+
+	// Set the port of the remote address, if any
+	clientPort := ""
+	if _, port, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		clientPort = port
+	}
+	repl.Set(e.key("request.client_port"), clientPort)
+
+	// Set the local address of the connection the client connected to
+	localAddr := ""
+	if addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+		localAddr = addr.String()
+	}
+	repl.Set(e.key("server.local_addr"), localAddr)
+
+	// Set the name of the Caddy server handling the request, falling back to the local address
+	serverName := localAddr
+	if srv, ok := r.Context().Value(caddyhttp.ServerCtxKey).(*caddyhttp.Server); ok && srv.Name() != "" {
+		serverName = srv.Name()
+	}
+	repl.Set(e.key("server.name"), serverName)
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"strings"
+
+	"github.com/caddyserver/caddy/v2"
+	"github.com/shirou/gopsutil/v4/sensors"
+	"go.uber.org/zap"
+)
+
+// setSensorsPlaceholders sets placeholders for the current temperature of each hardware sensor and
+// the average CPU temperature. Only the latter is set (empty) if no sensors are available on this platform.
+func (e ExtraPlaceholders) setSensorsPlaceholders(repl *caddy.Replacer) {
+	repl.Set(e.key("sensors.cpu_temp"), "")
+
+	temps, err := e.sensorsCache.get(func() ([]sensors.TemperatureStat, error) {
+		temps, err := sensors.SensorsTemperatures()
+		// Platforms without sensor support are treated like platforms without sensors.
+		if isNotImplemented(err) {
+			return nil, nil
+		}
+		// Some sensors may fail to be read while others succeed, so only treat it as an error if none were read.
+		if err != nil && len(temps) == 0 {
+			e.logger.Warn("Failed to retrieve sensor temperatures", zap.Error(err))
+			return nil, err
+		}
+		return temps, nil
+	})
+	if err != nil {
+		return
+	}
+	if len(temps) == 0 {
+		e.sensorsEmptyOnce.Do(func() {
+			e.logger.Debug("No temperature sensors available")
+		})
+		return
+	}
+	var cpuTempSum float64
+	var cpuTempCount int
+	for _, temp := range temps {
+		repl.Set(e.key("sensors.temp."+temp.SensorKey), temp.Temperature)
+
+		sensorKey := strings.ToLower(temp.SensorKey)
+		if strings.Contains(sensorKey, "core") || strings.Contains(sensorKey, "cpu") {
+			cpuTempSum += temp.Temperature
+			cpuTempCount++
+		}
+	}
+	if cpuTempCount > 0 {
+		repl.Set(e.key("sensors.cpu_temp"), cpuTempSum/float64(cpuTempCount))
+	}
+}
+
+// isNotImplemented reports whether err is the error returned by gopsutil on platforms without support
+// for a metric. As the error is defined in an internal package of gopsutil, it is matched by its message.
+func isNotImplemented(err error) bool {
+	return err != nil && err.Error() == "not implemented yet"
+}
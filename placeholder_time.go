@@ -2,48 +2,198 @@ package extraplaceholders
 
 import (
 	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"time"
+	"unicode"
 
 	"github.com/caddyserver/caddy/v2"
 )
 
 // setTimePlaceholders sets placeholders for date, time, and custom format,
-// using the provided time.Time. If isUTC is true, ".utc" is added in the placeholder path.
-func (e ExtraPlaceholders) setTimePlaceholders(repl *caddy.Replacer, t time.Time, isUTC bool) {
-	// Determine the base path, with or without ".utc"
-	base := "extra.time.now"
-	if isUTC {
-		base += ".utc"
-	}
-
+// using the provided time.Time. All placeholders are set below the given base path
+// (e.g., "extra.time.now" or "extra.time.now.utc").
+func (e ExtraPlaceholders) setTimePlaceholders(repl *caddy.Replacer, t time.Time, base string) {
 	// Placeholder support
 	// Dynamically resolve the time format using the replacer
 	timeFormatCustom := repl.ReplaceAll(e.TimeFormatCustom, defaultTimeFormatCustom)
 
 	// Set date and time components with the specified base path
+	repl.Set(fmt.Sprintf("%s.year", base), t.Year())
+	repl.Set(fmt.Sprintf("%s.year_short", base), fmt.Sprintf("%02d", t.Year()%100))
 	repl.Set(fmt.Sprintf("%s.month", base), int(t.Month()))
 	repl.Set(fmt.Sprintf("%s.month_padded", base), fmt.Sprintf("%02d", t.Month()))
 	repl.Set(fmt.Sprintf("%s.day", base), t.Day())
 	repl.Set(fmt.Sprintf("%s.day_padded", base), fmt.Sprintf("%02d", t.Day()))
 	repl.Set(fmt.Sprintf("%s.hour", base), t.Hour())
 	repl.Set(fmt.Sprintf("%s.hour_padded", base), fmt.Sprintf("%02d", t.Hour()))
+	hour12 := (t.Hour()+11)%12 + 1
+	repl.Set(fmt.Sprintf("%s.hour12", base), hour12)
+	repl.Set(fmt.Sprintf("%s.hour12_padded", base), fmt.Sprintf("%02d", hour12))
+	repl.Set(fmt.Sprintf("%s.ampm", base), t.Format("PM"))
 	repl.Set(fmt.Sprintf("%s.minute", base), t.Minute())
 	repl.Set(fmt.Sprintf("%s.minute_padded", base), fmt.Sprintf("%02d", t.Minute()))
 	repl.Set(fmt.Sprintf("%s.second", base), t.Second())
 	repl.Set(fmt.Sprintf("%s.second_padded", base), fmt.Sprintf("%02d", t.Second()))
 
+	// Set sub-second components
+	repl.Set(fmt.Sprintf("%s.millisecond", base), t.Nanosecond()/1e6)
+	repl.Set(fmt.Sprintf("%s.microsecond", base), t.Nanosecond()/1e3)
+	repl.Set(fmt.Sprintf("%s.nanosecond", base), t.Nanosecond())
+
 	// Set timezone offset and name
 	repl.Set(fmt.Sprintf("%s.timezone_offset", base), t.Format("-0700"))
-	repl.Set(fmt.Sprintf("%s.timezone_name", base), t.Format("MST"))
+	_, offsetSeconds := t.Zone()
+	repl.Set(fmt.Sprintf("%s.timezone_offset_minutes", base), offsetSeconds/60)
+	repl.Set(fmt.Sprintf("%s.timezone_name", base), timezoneName(t))
 
 	// Set the day of the week as an integer (Sunday = 0, Monday = 1, ..., Saturday = 6)
 	repl.Set(fmt.Sprintf("%s.weekday_int", base), int(t.Weekday()))
 
+	// Set the name of the day of the week and its number, both with Go's convention (Sunday = 0)
+	// and the ISO 8601 convention (Monday = 1, ..., Sunday = 7)
+	repl.Set(fmt.Sprintf("%s.weekday", base), t.Weekday().String())
+	repl.Set(fmt.Sprintf("%s.weekday_num", base), int(t.Weekday()))
+	weekdayISO := int(t.Weekday())
+	if weekdayISO == 0 {
+		weekdayISO = 7
+	}
+	repl.Set(fmt.Sprintf("%s.weekday_num_iso", base), weekdayISO)
+
+	// Set whether the day is on a weekend (Saturday or Sunday) or a weekday
+	isWeekend := t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+	repl.Set(fmt.Sprintf("%s.is_weekend", base), isWeekend)
+	repl.Set(fmt.Sprintf("%s.is_weekday", base), !isWeekend)
+
+	// Set whether the time is within the configured business hours on a weekday
+	if e.businessHoursSet {
+		minute := t.Hour()*60 + t.Minute()
+		repl.Set(fmt.Sprintf("%s.is_business_hours", base), !isWeekend && minute >= e.businessHoursStart && minute < e.businessHoursEnd)
+	}
+
+	// Set the quarter of the year
+	quarter := (int(t.Month())-1)/3 + 1
+	repl.Set(fmt.Sprintf("%s.quarter", base), quarter)
+	repl.Set(fmt.Sprintf("%s.quarter_label", base), fmt.Sprintf("Q%d", quarter))
+
+	// Set the day of the year and the number of days in the current month.
+	// Day 0 of the next month normalizes to the last day of the current month.
+	repl.Set(fmt.Sprintf("%s.year_day", base), t.YearDay())
+	repl.Set(fmt.Sprintf("%s.ordinal_date", base), fmt.Sprintf("%04d-%03d", t.Year(), t.YearDay()))
+	repl.Set(fmt.Sprintf("%s.days_in_month", base), time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day())
+
+	// Set the number of full seconds until the next midnight, which respects DST changes of the location
+	nextMidnight := time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+	repl.Set(fmt.Sprintf("%s.seconds_to_midnight", base), int64(nextMidnight.Sub(t)/time.Second))
+
 	// Set ISO week and year components
 	isoYear, isoWeek := t.ISOWeek()
 	repl.Set(fmt.Sprintf("%s.iso_week", base), isoWeek)
 	repl.Set(fmt.Sprintf("%s.iso_year", base), isoYear)
+	repl.Set(fmt.Sprintf("%s.iso_week_padded", base), fmt.Sprintf("%02d", isoWeek))
+	repl.Set(fmt.Sprintf("%s.iso_yearweek", base), fmt.Sprintf("%04d-W%02d", isoYear, isoWeek))
+
+	// Set standard format placeholders
+	repl.Set(fmt.Sprintf("%s.rfc3339", base), t.Format(time.RFC3339))
+	repl.Set(fmt.Sprintf("%s.rfc1123", base), t.Format(time.RFC1123))
+
+	// Set Unix timestamps, which are independent of the timezone
+	repl.Set(fmt.Sprintf("%s.unix", base), t.Unix())
+	repl.Set(fmt.Sprintf("%s.unix_milli", base), t.UnixMilli())
 
 	// Set custom time format placeholder
-	repl.Set(fmt.Sprintf("%s.custom", base), t.Format(timeFormatCustom))
+	custom := t.Format(timeFormatCustom)
+	repl.Set(fmt.Sprintf("%s.custom", base), custom)
+	repl.Set(fmt.Sprintf("%s.custom_urlenc", base), url.QueryEscape(custom))
+
+	// Set the custom time format applied to the Unix epoch for checking the layout
+	repl.Set(fmt.Sprintf("%s.custom_epoch", base), time.Unix(0, 0).In(t.Location()).Format(timeFormatCustom))
+
+	// Set named custom time format placeholders
+	for name, format := range e.TimeFormatsCustom {
+		repl.Set(fmt.Sprintf("%s.custom.%s", base, name), t.Format(repl.ReplaceAll(format, defaultTimeFormatCustom)))
+	}
+}
+
+// parseClockTime parses a 24h time of day in the format HH:MM (e.g., 09:00) and returns it in minutes since midnight.
+func parseClockTime(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// suspiciousTimeFormat checks the given Go time layout by formatting a sample time with it
+// and returns the reason if it looks like a typo, or an empty string otherwise. Layouts that
+// contain placeholders can only be checked once resolved per request, so they are skipped.
+func suspiciousTimeFormat(layout string) string {
+	if layout == "" || strings.Contains(layout, "{") {
+		return ""
+	}
+	// All components of the sample time differ from the reference time, so any layout element changes the output.
+	sample := time.Date(2009, time.November, 17, 20, 34, 58, 651387237, time.FixedZone("XYZ", 5*60*60+30*60))
+	formatted := sample.Format(layout)
+	if formatted == layout {
+		return "contains no layout elements of the reference time Mon Jan 2 15:04:05 MST 2006 (e.g., use 2006-01-02 instead of YYYY-MM-DD)"
+	}
+	if _, err := time.Parse(layout, formatted); err != nil {
+		return "formatted time cannot be parsed back: " + err.Error()
+	}
+	return ""
+}
+
+// timezoneName returns the timezone abbreviation of t (e.g., CEST). Go only knows the abbreviations
+// from the zoneinfo database and formats the numeric offset (e.g., -03) otherwise. In that case,
+// the IANA location name (e.g., America/Sao_Paulo) is returned instead, if available.
+func timezoneName(t time.Time) string {
+	abbr := t.Format("MST")
+	for _, c := range abbr {
+		if !unicode.IsLetter(c) {
+			name := t.Location().String()
+			if name == "Local" {
+				// Go names the local timezone "Local", regardless of where it was loaded from.
+				name = localZoneName()
+			}
+			if name != "" {
+				return name
+			}
+			break
+		}
+	}
+	return abbr
+}
+
+// localZoneName returns the IANA name of the local timezone, determined once from the TZ environment
+// variable or the target of the /etc/localtime symlink, or an empty string if it cannot be determined.
+var localZoneName = sync.OnceValue(func() string {
+	tz, ok := os.LookupEnv("TZ")
+	if !ok {
+		target, err := os.Readlink("/etc/localtime")
+		if err != nil {
+			return ""
+		}
+		tz = target
+	}
+	return zoneNameFromTZ(tz)
+})
+
+// zoneNameFromTZ returns the IANA name of the timezone from a TZ value (e.g., ":Europe/Berlin")
+// or a path below a zoneinfo directory (e.g., "/usr/share/zoneinfo/America/Sao_Paulo").
+// It returns an empty string if the name is not a loadable IANA timezone.
+func zoneNameFromTZ(tz string) string {
+	name := strings.TrimPrefix(tz, ":")
+	if i := strings.LastIndex(name, "zoneinfo/"); i >= 0 {
+		name = name[i+len("zoneinfo/"):]
+	}
+	if name == "" || filepath.IsAbs(name) {
+		return ""
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return ""
+	}
+	return name
 }
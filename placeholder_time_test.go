@@ -0,0 +1,129 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"testing"
+	"time"
+
+	"github.com/caddyserver/caddy/v2"
+)
+
+func TestTimezoneName(t *testing.T) {
+	loadLocation := func(name string) *time.Location {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			t.Skipf("timezone %s not available: %v", name, err)
+		}
+		return loc
+	}
+	date := time.Date(2024, time.July, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		loc  func() *time.Location
+		want string
+	}{
+		{"UTC", func() *time.Location { return time.UTC }, "UTC"},
+		{"abbreviation", func() *time.Location { return loadLocation("Asia/Kolkata") }, "IST"},
+		{"numeric abbreviation", func() *time.Location { return loadLocation("America/Sao_Paulo") }, "America/Sao_Paulo"},
+		{"fixed offset without name", func() *time.Location { return time.FixedZone("", -3*60*60) }, "-0300"},
+		{"fixed offset with name", func() *time.Location { return time.FixedZone("UTC-3", -3*60*60) }, "UTC-3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := timezoneName(date.In(tt.loc())); got != tt.want {
+				t.Errorf("timezoneName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestZoneNameFromTZ(t *testing.T) {
+	if _, err := time.LoadLocation("America/Sao_Paulo"); err != nil {
+		t.Skipf("timezone America/Sao_Paulo not available: %v", err)
+	}
+
+	tests := []struct {
+		tz   string
+		want string
+	}{
+		{"America/Sao_Paulo", "America/Sao_Paulo"},
+		{":America/Sao_Paulo", "America/Sao_Paulo"},
+		{"/usr/share/zoneinfo/America/Sao_Paulo", "America/Sao_Paulo"},
+		{"../usr/share/zoneinfo/America/Sao_Paulo", "America/Sao_Paulo"},
+		{"/etc/custom-zone", ""},
+		{"Invalid/Zone", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := zoneNameFromTZ(tt.tz); got != tt.want {
+			t.Errorf("zoneNameFromTZ(%q) = %q, want %q", tt.tz, got, tt.want)
+		}
+	}
+}
+
+// formatCustomTime returns the `{extra.time.now.custom}` and `{extra.time.now.custom_epoch}` placeholders
+// set by setTimePlaceholders for the given custom time format and time.
+func formatCustomTime(tb testing.TB, layout string, t time.Time) (custom, epoch string) {
+	tb.Helper()
+
+	e := ExtraPlaceholders{Prefix: defaultPrefix, TimeFormatCustom: layout}
+	repl := caddy.NewReplacer()
+	e.setTimePlaceholders(repl, t, e.key("time.now"))
+	custom, _ = repl.GetString("extra.time.now.custom")
+	epoch, _ = repl.GetString("extra.time.now.custom_epoch")
+	return custom, epoch
+}
+
+func TestCustomTimeFormatFractionalSeconds(t *testing.T) {
+	date := time.Date(2024, time.March, 5, 14, 30, 15, 120450000, time.UTC)
+
+	tests := []struct {
+		layout    string
+		want      string
+		wantEpoch string
+		// precision is the resolution of the fractional seconds that survives a round trip.
+		precision time.Duration
+	}{
+		{"2006-01-02 15:04:05.000", "2024-03-05 14:30:15.120", "1970-01-01 00:00:00.000", time.Millisecond},
+		{"2006-01-02 15:04:05.000000000", "2024-03-05 14:30:15.120450000", "1970-01-01 00:00:00.000000000", time.Nanosecond},
+		{"2006-01-02 15:04:05.999", "2024-03-05 14:30:15.12", "1970-01-01 00:00:00", time.Millisecond},
+		{"2006-01-02 15:04:05,000", "2024-03-05 14:30:15,120", "1970-01-01 00:00:00,000", time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			if reason := suspiciousTimeFormat(tt.layout); reason != "" {
+				t.Errorf("suspiciousTimeFormat() = %q, want no reason", reason)
+			}
+
+			got, epoch := formatCustomTime(t, tt.layout, date)
+			if got != tt.want {
+				t.Errorf("extra.time.now.custom = %q, want %q", got, tt.want)
+			}
+			if epoch != tt.wantEpoch {
+				t.Errorf("extra.time.now.custom_epoch = %q, want %q", epoch, tt.wantEpoch)
+			}
+
+			parsed, err := time.Parse(tt.layout, got)
+			if err != nil {
+				t.Fatalf("time.Parse(%q, %q) error = %v", tt.layout, got, err)
+			}
+			if want := date.Truncate(tt.precision); !parsed.Equal(want) {
+				t.Errorf("round trip = %v, want %v", parsed, want)
+			}
+		})
+	}
+}
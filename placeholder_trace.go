@@ -0,0 +1,53 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	crand "crypto/rand"
+	"encoding/hex"
+
+	"github.com/caddyserver/caddy/v2"
+)
+
+// setTracePlaceholders sets placeholders for a random trace ID and span ID per request,
+// with the sizes of OpenTelemetry and W3C Trace Context (16 and 8 bytes).
+func (e ExtraPlaceholders) setTracePlaceholders(repl *caddy.Replacer) {
+	if id, err := randTraceID(16); err == nil {
+		repl.Set(e.key("trace.id"), id)
+	} else {
+		e.setRandError(repl, e.key("trace.id"), err)
+	}
+	if id, err := randTraceID(8); err == nil {
+		repl.Set(e.key("trace.span"), id)
+	} else {
+		e.setRandError(repl, e.key("trace.span"), err)
+	}
+}
+
+// randTraceID returns a hex-encoded ID of n random bytes from crypto/rand.
+// An ID of all zeros is invalid in W3C Trace Context, so it is generated again in that case.
+func randTraceID(n int) (string, error) {
+	b := make([]byte, n)
+	for {
+		if _, err := crand.Read(b); err != nil {
+			return "", err
+		}
+		for _, c := range b {
+			if c != 0 {
+				return hex.EncodeToString(b), nil
+			}
+		}
+	}
+}
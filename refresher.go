@@ -0,0 +1,121 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/load"
+	"github.com/shirou/gopsutil/v4/mem"
+)
+
+// hostSnapshot holds the host metrics retrieved by the background refresher, together with the errors of their retrieval.
+type hostSnapshot struct {
+	loadAvg    *load.AvgStat
+	loadAvgErr error
+	swap       *mem.SwapMemoryStat
+	swapErr    error
+	cpuTotal   float64
+	cpuPer     []float64
+	cpuErr     error
+}
+
+// refresher periodically retrieves the host metrics in a background goroutine, so that
+// requests only read the latest snapshot without any system calls.
+type refresher struct {
+	snapshot atomic.Pointer[hostSnapshot]
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// startRefresher takes an initial snapshot of the host metrics and starts a goroutine
+// that refreshes it every interval until stop is called.
+func (e ExtraPlaceholders) startRefresher(interval time.Duration) *refresher {
+	r := &refresher{stop: make(chan struct{}), done: make(chan struct{})}
+	r.snapshot.Store(e.takeHostSnapshot())
+
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r.snapshot.Store(e.takeHostSnapshot())
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+
+	return r
+}
+
+// stopAndWait signals the refresher goroutine to stop and waits until it has exited.
+// It is safe to call more than once. Requests keep reading the last snapshot afterwards.
+func (r *refresher) stopAndWait() {
+	r.stopOnce.Do(func() { close(r.stop) })
+	<-r.done
+}
+
+// takeHostSnapshot retrieves the host metrics of the placeholder groups that are not disabled.
+func (e ExtraPlaceholders) takeHostSnapshot() *hostSnapshot {
+	s := &hostSnapshot{}
+	if e.groupEnabled("loadavg") && !e.DisableLoadavgPlaceholders {
+		s.loadAvg, s.loadAvgErr = load.Avg()
+	}
+	if e.groupEnabled("mem") && !e.DisableMemPlaceholders {
+		s.swap, s.swapErr = mem.SwapMemory()
+	}
+	if e.groupEnabled("cpu") && !e.DisableCPUPlaceholders {
+		s.cpuTotal, s.cpuPer, s.cpuErr = e.cpuSampler.percent()
+	}
+	return s
+}
+
+// loadAvg returns the load average from the background refresher if enabled, or from the cache otherwise.
+func (e ExtraPlaceholders) loadAvg() (*load.AvgStat, error) {
+	if e.refresher != nil {
+		s := e.refresher.snapshot.Load()
+		return s.loadAvg, s.loadAvgErr
+	}
+	return e.loadavgCache.get(load.Avg)
+}
+
+// swapMemory returns the swap memory usage from the background refresher if enabled, or retrieves it otherwise.
+func (e ExtraPlaceholders) swapMemory() (*mem.SwapMemoryStat, error) {
+	if e.refresher != nil {
+		s := e.refresher.snapshot.Load()
+		return s.swap, s.swapErr
+	}
+	return mem.SwapMemory()
+}
+
+// cpuPercent returns the aggregate and per-CPU utilization from the background refresher if enabled,
+// i.e. over the last refresh interval, or from the cache otherwise, i.e. since the previous reading.
+func (e ExtraPlaceholders) cpuPercent() (float64, []float64, error) {
+	if e.refresher != nil {
+		s := e.refresher.snapshot.Load()
+		return s.cpuTotal, s.cpuPer, s.cpuErr
+	}
+	u, err := e.cpuCache.get(func() (cpuUtilization, error) {
+		total, perCPU, err := e.cpuSampler.percent()
+		return cpuUtilization{total: total, perCPU: perCPU}, err
+	})
+	return u.total, u.perCPU, err
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 Steffen Busch
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraplaceholders
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+// TestRefresherStopsOnCleanup verifies that the background refresher goroutine exits when the handler is cleaned up.
+func TestRefresherStopsOnCleanup(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	e := newTestHandler(t, `extra_placeholders {
+		refresh_interval 10ms
+	}`)
+	r := e.refresher
+	if r == nil {
+		t.Fatal("refresher was not started")
+	}
+	// Let the refresher tick at least once.
+	time.Sleep(30 * time.Millisecond)
+	serveTestRequest(t, e)
+
+	if err := e.Cleanup(); err != nil {
+		t.Fatalf("Cleanup() error = %v", err)
+	}
+	select {
+	case <-r.done:
+	default:
+		t.Fatal("refresher goroutine has not exited after Cleanup()")
+	}
+	// Requests still in flight after Cleanup must not panic and keep reading the last snapshot.
+	serveTestRequest(t, e)
+
+	// Other goroutines, e.g. of the runtime, may need a moment to wind down as well.
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("number of goroutines = %d after Cleanup(), want at most %d", after, before)
+	}
+}